@@ -22,18 +22,31 @@ import (
 
 // ChannelSpec defines the desired state of Channel
 type ChannelSpec struct {
-	// Name of the slack channel
-	// +required
-	Name string `json:"name"`
+	// Name of the slack channel, derived from the name template of the operator config if empty
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Name of the SlackWorkspace in the namespace of the Channel whose token is used, the operator's default
+	// workspace is used if empty
+	// +optional
+	Workspace string `json:"workspace,omitempty"`
 
 	// Make the channel private or public
 	// +optional
 	Private bool `json:"private,omitempty"`
 
-	// List of user IDs of the users to invite
-	// +kubebuilder:validation:MinItems=1
-	// +required
-	Users []string `json:"users"`
+	// Emails of the users to invite
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// Handles or IDs of slack user groups whose members are invited in addition to the users. The members are
+	// resolved again on every resync, so the channel follows changes of the user groups
+	// +optional
+	UserGroups []string `json:"userGroups,omitempty"`
+
+	// Kubernetes RBAC bindings whose User subjects are invited in addition to the users
+	// +optional
+	MembersFrom *MembersFrom `json:"membersFrom,omitempty"`
 
 	// Description of the channel
 	// +optional
@@ -42,6 +55,342 @@ type ChannelSpec struct {
 	// Topic of the channel
 	// +optional
 	Topic string `json:"topic,omitempty"`
+
+	// Adopt an existing slack channel instead of creating a new one
+	// +optional
+	Adopt *ChannelAdoption `json:"adopt,omitempty"`
+
+	// What happens to the slack channel when the Channel resource is deleted, defaulted from the operator config
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Suffix appended to the channel name before archiving it, required by the RenameThenArchive deletion policy.
+	// Defaulted from the operator config if the deletion policy is defaulted as well
+	// +optional
+	ArchiveSuffix string `json:"archiveSuffix,omitempty"`
+
+	// How changes made directly on slack are handled, defaulted from the operator config
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// How the members of the slack channel are managed, defaulted from the operator config
+	// +optional
+	MembershipPolicy MembershipPolicy `json:"membershipPolicy,omitempty"`
+
+	// Emails of the channel managers, who are invited like the users. Requires an admin token of an Enterprise Grid
+	// organization
+	// +optional
+	Managers []string `json:"managers,omitempty"`
+
+	// Who can post in the channel, posting is left untouched if empty. Requires an admin token of an Enterprise Grid
+	// organization
+	// +optional
+	PostingPolicy PostingPolicy `json:"postingPolicy,omitempty"`
+
+	// External organizations to share the channel with through Slack Connect
+	// +optional
+	SharedWith []SharedInvitation `json:"sharedWith,omitempty"`
+
+	// Links bookmarked in the channel, identified by their titles
+	// +optional
+	Bookmarks []Bookmark `json:"bookmarks,omitempty"`
+
+	// Messages posted and pinned in the channel, identified by their texts
+	// +optional
+	PinnedMessages []PinnedMessage `json:"pinnedMessages,omitempty"`
+
+	// Message posted once in the channel after it is created, changes to it edit the posted message
+	// +optional
+	WelcomeMessage *WelcomeMessage `json:"welcomeMessage,omitempty"`
+
+	// Kubernetes events of the namespace of the Channel that are posted in the channel. Requires the notifications
+	// feature of the operator
+	// +optional
+	Notifications *Notifications `json:"notifications,omitempty"`
+
+	// Interval at which the slack channel is checked for drift, overrides the operator wide resync period. 0s disables resync
+	// +optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+}
+
+// DeletionPolicy describes what happens to the slack channel when the Channel resource is deleted
+// +kubebuilder:validation:Enum=Archive;Retain;RenameThenArchive
+type DeletionPolicy string
+
+const (
+	// ArchiveDeletionPolicy archives the slack channel
+	ArchiveDeletionPolicy DeletionPolicy = "Archive"
+
+	// RetainDeletionPolicy leaves the slack channel untouched
+	RetainDeletionPolicy DeletionPolicy = "Retain"
+
+	// RenameThenArchiveDeletionPolicy appends the archive suffix to the channel name and then archives it
+	RenameThenArchiveDeletionPolicy DeletionPolicy = "RenameThenArchive"
+)
+
+// UserEmailsAnnotation is the annotation of a RoleBinding or ClusterRoleBinding which maps the names of its User
+// subjects to their emails as a JSON object, subjects whose names are emails don't need to be mapped
+const UserEmailsAnnotation = "slack.stakater.com/user-emails"
+
+// MembersFrom selects the RBAC bindings whose User subjects are members of the channel
+type MembersFrom struct {
+	// RoleBindings in the namespace of the Channel
+	// +optional
+	RoleBindings *BindingSelector `json:"roleBindings,omitempty"`
+
+	// ClusterRoleBindings, at least one name or role has to be given
+	// +optional
+	ClusterRoleBindings *BindingSelector `json:"clusterRoleBindings,omitempty"`
+}
+
+// BindingSelector selects RBAC bindings by name or by the role they bind, all bindings are selected if both are empty
+type BindingSelector struct {
+	// Names of the bindings
+	// +optional
+	Names []string `json:"names,omitempty"`
+
+	// Names of the Roles or ClusterRoles the bindings refer to. A name selects bindings to a Role or a ClusterRole with
+	// the name, prefixing it with the kind, e.g. ClusterRole/edit, only selects the bindings to a role of that kind.
+	// +optional
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Matches returns true if the binding with the given name, which refers to the role of the given kind and name, is
+// selected
+func (selector *BindingSelector) Matches(name string, roleKind string, roleName string) bool {
+	return matchesAny(selector.Names, name) && (len(selector.Roles) == 0 ||
+		containsString(selector.Roles, roleName) || containsString(selector.Roles, roleKind+"/"+roleName))
+}
+
+// containsString returns true if the list contains the value
+func containsString(list []string, value string) bool {
+	for _, element := range list {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny returns true if the list is empty or contains the value
+func matchesAny(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, element := range list {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ChannelAdoption identifies an existing slack channel to be managed by the Channel resource
+type ChannelAdoption struct {
+	// ID of the existing slack channel
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name of the existing slack channel
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Allow adoption even if members of the existing channel that are not listed in users will be removed
+	// +optional
+	AllowDestructiveChanges bool `json:"allowDestructiveChanges,omitempty"`
+}
+
+// DriftPolicy describes how changes made directly on slack are handled
+// +kubebuilder:validation:Enum=Enforce;ReportOnly
+type DriftPolicy string
+
+const (
+	// EnforceDriftPolicy overwrites changes made on slack with the Channel spec
+	EnforceDriftPolicy DriftPolicy = "Enforce"
+
+	// ReportOnlyDriftPolicy reports changes made on slack in the Channel status without overwriting them
+	ReportOnlyDriftPolicy DriftPolicy = "ReportOnly"
+)
+
+// SharedInvitation is a Slack Connect invitation of an external organization to the channel
+type SharedInvitation struct {
+	// Email of the person in the external organization who receives the invitation
+	// +kubebuilder:validation:MinLength=1
+	Email string `json:"email"`
+
+	// ID of the external organization which is expected to accept the invitation. The invitation is accepted once a
+	// member of the organization joined the channel, or the recipient if empty
+	// +optional
+	TeamID string `json:"teamID,omitempty"`
+
+	// Don't allow the external organization to invite others or manage the channel
+	// +optional
+	ExternalLimited bool `json:"externalLimited,omitempty"`
+}
+
+// Bookmark is a link bookmarked in the channel
+type Bookmark struct {
+	// Title of the bookmark
+	// +kubebuilder:validation:MinLength=1
+	Title string `json:"title"`
+
+	// Link the bookmark opens
+	// +kubebuilder:validation:MinLength=1
+	Link string `json:"link"`
+
+	// Emoji shown next to the title, e.g. :book:
+	// +optional
+	Emoji string `json:"emoji,omitempty"`
+}
+
+// PinnedMessage is a message posted and pinned in the channel
+type PinnedMessage struct {
+	// Text of the message
+	// +kubebuilder:validation:MinLength=1
+	Text string `json:"text"`
+}
+
+// WelcomeMessage is a message posted in the channel after it is created. Its text and blocks are Go templates over the
+// Channel, e.g. {{ .Spec.Name }}, and the labels of its namespace, e.g. {{ .NamespaceLabels.team }}
+type WelcomeMessage struct {
+	// Text of the message, which is the fallback shown in notifications if there are blocks
+	// +kubebuilder:validation:MinLength=1
+	Text string `json:"text"`
+
+	// Block Kit blocks of the message as a JSON array
+	// +optional
+	Blocks string `json:"blocks,omitempty"`
+}
+
+// Notifications selects the Kubernetes events of the namespace of the Channel that are posted in the channel
+type Notifications struct {
+	// Events of the namespace
+	// +optional
+	Events *EventNotifications `json:"events,omitempty"`
+
+	// Pods of the namespace whose containers fail, e.g. crash loop, are OOM killed or can't pull their images
+	// +optional
+	PodFailures *PodFailureNotifications `json:"podFailures,omitempty"`
+
+	// Rollouts of the Deployments of the namespace that complete or exceed their progress deadline
+	// +optional
+	DeploymentRollouts *DeploymentRolloutNotifications `json:"deploymentRollouts,omitempty"`
+
+	// Interval during which the same notification is posted only once, defaults to 10m
+	// +optional
+	ThrottlePeriod *metav1.Duration `json:"throttlePeriod,omitempty"`
+
+	// Maximum number of notifications posted in the channel per throttle period, further ones are dropped. Defaults
+	// to 20
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Limit int32 `json:"limit,omitempty"`
+}
+
+// EventNotifications selects Events by their type and reason
+type EventNotifications struct {
+	// Types of the events, Normal or Warning, all types if empty
+	// +optional
+	Types []string `json:"types,omitempty"`
+
+	// Reasons of the events, e.g. BackOff or FailedScheduling, all reasons if empty
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// PodFailureNotifications selects failing Pods by the reason of the failure and their labels
+type PodFailureNotifications struct {
+	// Reasons of the failures, e.g. CrashLoopBackOff, ImagePullBackOff, OOMKilled or Evicted, all reasons if empty
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+
+	// Selects the Pods by their labels, all Pods if empty
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// DeploymentRolloutNotifications selects Deployments by their labels
+type DeploymentRolloutNotifications struct {
+	// Selects the Deployments by their labels, all Deployments if empty
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// MembershipPolicy describes how the members of the slack channel are managed
+// +kubebuilder:validation:Enum=Authoritative;Additive;Ignore
+type MembershipPolicy string
+
+const (
+	// AuthoritativeMembershipPolicy invites the missing members and removes the members that are not listed
+	AuthoritativeMembershipPolicy MembershipPolicy = "Authoritative"
+
+	// AdditiveMembershipPolicy invites the missing members and never removes anyone
+	AdditiveMembershipPolicy MembershipPolicy = "Additive"
+
+	// IgnoreMembershipPolicy leaves the members of the slack channel untouched
+	IgnoreMembershipPolicy MembershipPolicy = "Ignore"
+)
+
+// PostingPolicy describes who can post in the slack channel
+// +kubebuilder:validation:Enum=Members;Managers;Admins
+type PostingPolicy string
+
+const (
+	// MembersPostingPolicy allows all members and admins of the workspace to post
+	MembersPostingPolicy PostingPolicy = "Members"
+
+	// ManagersPostingPolicy only allows the channel managers and the admins of the workspace to post
+	ManagersPostingPolicy PostingPolicy = "Managers"
+
+	// AdminsPostingPolicy only allows the admins of the workspace to post
+	AdminsPostingPolicy PostingPolicy = "Admins"
+)
+
+const (
+	// DriftedConditionType is the condition set when the slack channel differs from the Channel spec
+	DriftedConditionType string = "Drifted"
+
+	// TokenUnavailableConditionType is the condition set while the operator has no valid API token for the workspace
+	TokenUnavailableConditionType string = "TokenUnavailable"
+
+	// MembersDegradedConditionType is the condition set while some members could not be added to the slack channel
+	MembersDegradedConditionType string = "MembersDegraded"
+
+	reconcileSuccessConditionType string = "ReconcileSuccess"
+	reconcileErrorConditionType   string = "ReconcileError"
+)
+
+// FieldDrift describes a field whose value on slack differs from the Channel spec
+type FieldDrift struct {
+	// Value in the Channel spec
+	Desired string `json:"desired"`
+
+	// Value on slack
+	Actual string `json:"actual"`
+}
+
+// ChannelDrift describes the differences between the Channel spec and the slack channel
+type ChannelDrift struct {
+	// Name of the slack channel
+	// +optional
+	Name *FieldDrift `json:"name,omitempty"`
+
+	// Topic of the slack channel
+	// +optional
+	Topic *FieldDrift `json:"topic,omitempty"`
+
+	// Description of the slack channel
+	// +optional
+	Description *FieldDrift `json:"description,omitempty"`
+
+	// Emails of the users that are not members of the slack channel, unknown and deactivated users are reported in
+	// the members instead
+	// +optional
+	MissingUsers []string `json:"missingUsers,omitempty"`
+
+	// Emails of the members of the slack channel that are not listed in users
+	// +optional
+	ExtraUsers []string `json:"extraUsers,omitempty"`
 }
 
 // ChannelStatus defines the observed state of Channel
@@ -49,10 +398,138 @@ type ChannelStatus struct {
 	// ID of the slack channel
 	ID string `json:"id"`
 
+	// Adopted is true when the slack channel existed before and was adopted by the Channel resource
+	// +optional
+	Adopted bool `json:"adopted,omitempty"`
+
+	// Generation of the Channel spec that was last applied to slack
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Differences between the Channel spec and the slack channel
+	// +optional
+	Drift *ChannelDrift `json:"drift,omitempty"`
+
+	// Membership status of each member of the channel, not reported with the Ignore membership policy
+	// +optional
+	Members []MemberStatus `json:"members,omitempty"`
+
+	// Slack Connect invitations that were sent
+	// +optional
+	SharedInvitations []SharedInvitationStatus `json:"sharedInvitations,omitempty"`
+
+	// Bookmarks that were added to the slack channel
+	// +optional
+	Bookmarks []BookmarkStatus `json:"bookmarks,omitempty"`
+
+	// Messages that were posted and pinned in the slack channel
+	// +optional
+	PinnedMessages []PinnedMessageStatus `json:"pinnedMessages,omitempty"`
+
+	// Welcome message that was posted in the slack channel
+	// +optional
+	WelcomeMessage *WelcomeMessageStatus `json:"welcomeMessage,omitempty"`
+
+	// Emails of the channel managers that were assigned on slack
+	// +optional
+	Managers []string `json:"managers,omitempty"`
+
+	// Posting policy that was applied on slack
+	// +optional
+	PostingPolicy PostingPolicy `json:"postingPolicy,omitempty"`
+
 	// Status conditions
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// BookmarkStatus is a bookmark added to the slack channel by the operator
+type BookmarkStatus struct {
+	// ID of the bookmark on slack
+	ID string `json:"id"`
+
+	// Title of the bookmark
+	Title string `json:"title"`
+}
+
+// PinnedMessageStatus is a message posted and pinned in the slack channel by the operator
+type PinnedMessageStatus struct {
+	// Timestamp of the message on slack
+	Timestamp string `json:"timestamp"`
+
+	// Text of the message
+	Text string `json:"text"`
+}
+
+// WelcomeMessageStatus is the welcome message posted in the slack channel by the operator
+type WelcomeMessageStatus struct {
+	// Timestamp of the message on slack
+	Timestamp string `json:"timestamp"`
+
+	// Checksum of the rendered text and blocks of the message, used to detect changes
+	Checksum string `json:"checksum"`
+}
+
+// SharedInvitationStatus is the state of a Slack Connect invitation
+type SharedInvitationStatus struct {
+	// Email the invitation was sent to
+	Email string `json:"email"`
+
+	// ID of the external organization which is expected to accept the invitation
+	// +optional
+	TeamID string `json:"teamID,omitempty"`
+
+	// ID of the invitation on slack
+	InviteID string `json:"inviteID"`
+
+	// State of the invitation
+	State SharedInvitationState `json:"state"`
+}
+
+// SharedInvitationState describes whether a Slack Connect invitation was accepted
+type SharedInvitationState string
+
+const (
+	// PendingSharedInvitationState means the external organization has not joined the channel yet
+	PendingSharedInvitationState SharedInvitationState = "Pending"
+
+	// AcceptedSharedInvitationState means the external organization joined the channel
+	AcceptedSharedInvitationState SharedInvitationState = "Accepted"
+)
+
+// MemberStatus is the membership status of a member of the channel
+type MemberStatus struct {
+	// Email of the member
+	Email string `json:"email"`
+
+	// ID of the slack user with the email
+	// +optional
+	UserID string `json:"userID,omitempty"`
+
+	// State of the membership
+	State MemberState `json:"state"`
+
+	// Error which kept the member from joining the channel
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// MemberState describes whether a member is in the slack channel
+type MemberState string
+
+const (
+	// JoinedMemberState means the user is in the slack channel
+	JoinedMemberState MemberState = "Joined"
+
+	// NotFoundMemberState means no slack user has the email
+	NotFoundMemberState MemberState = "NotFound"
+
+	// DeactivatedMemberState means the slack user is deactivated
+	DeactivatedMemberState MemberState = "Deactivated"
+
+	// InviteFailedMemberState means inviting the user to the slack channel failed
+	InviteFailedMemberState MemberState = "InviteFailed"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 
@@ -84,6 +561,30 @@ func (channel *Channel) GetReconcileStatus() []metav1.Condition {
 }
 
 // SetReconcileStatus - sets status, required for making Channel ConditionsStatusAware
+// Conditions other than the reconcile status, e.g. Drifted, are preserved
 func (channel *Channel) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	for _, condition := range channel.Status.Conditions {
+		if condition.Type != reconcileSuccessConditionType && condition.Type != reconcileErrorConditionType {
+			reconcileStatus = append(reconcileStatus, condition)
+		}
+	}
 	channel.Status.Conditions = reconcileStatus
 }
+
+// Fields returns the names of the fields that have drifted
+func (drift *ChannelDrift) Fields() []string {
+	var fields []string
+	if drift.Name != nil {
+		fields = append(fields, "name")
+	}
+	if drift.Topic != nil {
+		fields = append(fields, "topic")
+	}
+	if drift.Description != nil {
+		fields = append(fields, "description")
+	}
+	if len(drift.MissingUsers) > 0 || len(drift.ExtraUsers) > 0 {
+		fields = append(fields, "users")
+	}
+	return fields
+}
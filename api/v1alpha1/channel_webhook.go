@@ -17,10 +17,23 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode/utf8"
 
+	"github.com/stakater/slack-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -28,7 +41,28 @@ import (
 // log is for logging in this package.
 var channellog = logf.Log.WithName("channel-resource")
 
+const (
+	maxChannelNameLength = 80
+
+	// maxTopicLength is the maximum length of the topic and the description, which slack calls purpose, of a channel
+	maxTopicLength = 250
+)
+
+var (
+	// channelNameRegex matches the names slack allows for channels, which is also the charset of archive suffixes
+	channelNameRegex = regexp.MustCompile("^[a-z0-9_-]+$")
+
+	// illegalNameCharactersRegex matches the characters slack doesn't allow in channel names
+	illegalNameCharactersRegex = regexp.MustCompile("[^a-z0-9_-]+")
+)
+
+// channelReader lists the Channels of the cluster to reject a Channel for a slack channel that another Channel already
+// manages, the check is skipped if it is nil
+var channelReader client.Reader
+
 func (r *Channel) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	channelReader = mgr.GetClient()
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -44,7 +78,57 @@ var _ webhook.Defaulter = &Channel{}
 func (r *Channel) Default() {
 	channellog.Info("default", "name", r.Name)
 
-	// TODO(user): fill in your defaulting logic.
+	// The policies are written into the spec, so that later changes of the operator config don't change what happens
+	// to existing channels, e.g. when they are deleted
+	defaults := config.Get().ChannelDefaults
+	if r.Spec.DeletionPolicy == "" {
+		r.Spec.DeletionPolicy = DeletionPolicy(defaults.DeletionPolicy)
+		if r.Spec.DeletionPolicy == RenameThenArchiveDeletionPolicy && r.Spec.ArchiveSuffix == "" {
+			r.Spec.ArchiveSuffix = defaults.ArchiveSuffix
+		}
+	}
+	if r.Spec.DriftPolicy == "" {
+		r.Spec.DriftPolicy = DriftPolicy(defaults.DriftPolicy)
+	}
+	if r.Spec.MembershipPolicy == "" {
+		r.Spec.MembershipPolicy = MembershipPolicy(defaults.MembershipPolicy)
+	}
+
+	nameTemplate := defaults.NameTemplate
+	if r.Spec.Name == "" && nameTemplate != "" {
+		name, err := NameFromTemplate(r, nameTemplate)
+		if err != nil {
+			// The validation rejects the channel without a name and reports the error
+			channellog.Error(err, "Failed to derive the channel name from the name template", "name", r.Name)
+			return
+		}
+		r.Spec.Name = name
+	}
+}
+
+// NameFromTemplate renders the name template over the channel and makes the result a valid slack channel name, by
+// lowercasing it, replacing the illegal characters with hyphens and truncating it to the maximum length
+func NameFromTemplate(channel *Channel, nameTemplate string) (string, error) {
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var name bytes.Buffer
+	err = tmpl.Execute(&name, channel)
+	if err != nil {
+		return "", err
+	}
+
+	sanitized := illegalNameCharactersRegex.ReplaceAllString(strings.ToLower(name.String()), "-")
+	sanitized = strings.Trim(sanitized, "-")
+	if len(sanitized) > maxChannelNameLength {
+		sanitized = strings.TrimRight(sanitized[:maxChannelNameLength], "-")
+	}
+	if sanitized == "" {
+		return "", fmt.Errorf("Name template rendered %q, which contains no valid characters", name.String())
+	}
+	return sanitized, nil
 }
 
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
@@ -56,11 +140,9 @@ var _ webhook.Validator = &Channel{}
 func (r *Channel) ValidateCreate() error {
 	channellog.Info("validate create", "name", r.Name)
 
-	if len(r.Spec.Users) < 1 {
-		return fmt.Errorf("Users can not be empty")
-	}
-
-	return nil
+	errs := r.validateSpec()
+	errs = append(errs, r.validateUniqueName()...)
+	return r.invalid(errs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -72,11 +154,17 @@ func (r *Channel) ValidateUpdate(old runtime.Object) error {
 		return fmt.Errorf("Error casting old runtime object to %T from %T", oldChannel, old)
 	}
 
-	if len(r.Spec.Users) < 1 {
-		return fmt.Errorf("Users can not be empty")
+	errs := r.validateSpec()
+	if oldChannel.Spec.Workspace != r.Spec.Workspace {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "workspace"), r.Spec.Workspace, "is immutable and cannot be changed after Slack Channel has been created"))
 	}
+	errs = append(errs, validateImmutableFields(r, oldChannel)...)
 
-	return ValidateImmutableFields(r, oldChannel)
+	// Renaming the slack channel must not take the name of a slack channel managed by another Channel
+	if oldChannel.Spec.Name != r.Spec.Name {
+		errs = append(errs, r.validateUniqueName()...)
+	}
+	return r.invalid(errs)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -87,9 +175,326 @@ func (r *Channel) ValidateDelete() error {
 	return nil
 }
 
+// invalid returns the Invalid error of the API for the field errors, which reports their field paths, or nil if there
+// are none
+func (r *Channel) invalid(errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("Channel").GroupKind(), r.Name, errs)
+}
+
+func (r *Channel) validateSpec() field.ErrorList {
+	var errs field.ErrorList
+	if len(r.Spec.Users) < 1 && len(r.Spec.UserGroups) < 1 && r.Spec.MembersFrom == nil && len(r.Spec.Managers) < 1 &&
+		r.Spec.MembershipPolicy != IgnoreMembershipPolicy {
+		errs = append(errs, field.Required(field.NewPath("spec", "users"), "users, userGroups, membersFrom and managers can not all be empty unless the membership policy is Ignore"))
+	}
+
+	for _, validate := range []func(*Channel) field.ErrorList{ValidateName, ValidateTopicAndDescription, ValidateUsers, ValidateAdoption, ValidateDeletionPolicy, ValidateResyncPeriod, ValidateMembersFrom, ValidatePostingPolicy, ValidateSharedWith, ValidateBookmarks, ValidatePinnedMessages, ValidateWelcomeMessage, ValidateNotifications} {
+		errs = append(errs, validate(r)...)
+	}
+	return errs
+}
+
+// validateUniqueName rejects the channel if another Channel of the cluster has the same name in the same workspace,
+// i.e. both use the default workspace of the operator or the same SlackWorkspace of their namespace
+func (r *Channel) validateUniqueName() field.ErrorList {
+	if channelReader == nil || r.Spec.Name == "" {
+		return nil
+	}
+
+	namePath := field.NewPath("spec", "name")
+	channels := &ChannelList{}
+	err := channelReader.List(context.Background(), channels)
+	if err != nil {
+		return field.ErrorList{field.InternalError(namePath, err)}
+	}
+
+	for _, channel := range channels.Items {
+		if channel.Namespace == r.Namespace && channel.Name == r.Name {
+			continue
+		}
+		sameWorkspace := channel.Spec.Workspace == "" && r.Spec.Workspace == "" ||
+			channel.Namespace == r.Namespace && channel.Spec.Workspace == r.Spec.Workspace
+		if channel.Spec.Name == r.Spec.Name && sameWorkspace {
+			return field.ErrorList{field.Invalid(namePath, r.Spec.Name, fmt.Sprintf("is already the name of the slack channel of Channel %s/%s", channel.Namespace, channel.Name))}
+		}
+	}
+	return nil
+}
+
 func ValidateImmutableFields(newChannel *Channel, oldChannel *Channel) error {
+	return validateImmutableFields(newChannel, oldChannel).ToAggregate()
+}
+
+func validateImmutableFields(newChannel *Channel, oldChannel *Channel) field.ErrorList {
 	if oldChannel.Spec.Private != newChannel.Spec.Private {
-		return fmt.Errorf("Field 'isPrivate' is immutable and cannot be changed after Slack Channel has been created")
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "private"), newChannel.Spec.Private, "is immutable and cannot be changed after Slack Channel has been created")}
+	}
+	return nil
+}
+
+func ValidateName(channel *Channel) field.ErrorList {
+	namePath := field.NewPath("spec", "name")
+	name := channel.Spec.Name
+	if name == "" {
+		nameTemplate := config.Get().ChannelDefaults.NameTemplate
+		if nameTemplate != "" {
+			_, err := NameFromTemplate(channel, nameTemplate)
+			if err != nil {
+				return field.ErrorList{field.Required(namePath, fmt.Sprintf("can not be derived from the name template: %s", err.Error()))}
+			}
+		}
+		return field.ErrorList{field.Required(namePath, "")}
+	}
+
+	var errs field.ErrorList
+	if len(name) > maxChannelNameLength {
+		errs = append(errs, field.TooLong(namePath, name, maxChannelNameLength))
+	}
+	if !channelNameRegex.MatchString(name) {
+		errs = append(errs, field.Invalid(namePath, name, "can only contain lowercase letters, numbers, hyphens and underscores"))
+	}
+	return errs
+}
+
+func ValidateTopicAndDescription(channel *Channel) field.ErrorList {
+	var errs field.ErrorList
+	if utf8.RuneCountInString(channel.Spec.Topic) > maxTopicLength {
+		errs = append(errs, field.TooLong(field.NewPath("spec", "topic"), channel.Spec.Topic, maxTopicLength))
+	}
+	if utf8.RuneCountInString(channel.Spec.Description) > maxTopicLength {
+		errs = append(errs, field.TooLong(field.NewPath("spec", "description"), channel.Spec.Description, maxTopicLength))
+	}
+	return errs
+}
+
+func ValidateUsers(channel *Channel) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, validateEmails(field.NewPath("spec", "users"), channel.Spec.Users)...)
+	errs = append(errs, validateEmails(field.NewPath("spec", "managers"), channel.Spec.Managers)...)
+	return errs
+}
+
+// validateEmails rejects the emails that are invalid or listed more than once
+func validateEmails(path *field.Path, emails []string) field.ErrorList {
+	var errs field.ErrorList
+	seen := map[string]bool{}
+	for i, email := range emails {
+		if !isEmail(email) {
+			errs = append(errs, field.Invalid(path.Index(i), email, "must be an email address"))
+		}
+		if seen[strings.ToLower(email)] {
+			errs = append(errs, field.Duplicate(path.Index(i), email))
+		}
+		seen[strings.ToLower(email)] = true
+	}
+	return errs
+}
+
+// isEmail returns true if value is a plain email address, without a display name
+func isEmail(value string) bool {
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Address == value
+}
+
+func ValidateDeletionPolicy(channel *Channel) field.ErrorList {
+	suffix := channel.Spec.ArchiveSuffix
+	suffixPath := field.NewPath("spec", "archiveSuffix")
+
+	switch channel.Spec.DeletionPolicy {
+	case ArchiveDeletionPolicy, RetainDeletionPolicy:
+		if suffix != "" {
+			return field.ErrorList{field.Forbidden(suffixPath, fmt.Sprintf("can only be set with the %s deletion policy", RenameThenArchiveDeletionPolicy))}
+		}
+	case "":
+		// The operator default applies, the suffix is used if it is RenameThenArchive
+		if suffix != "" && !channelNameRegex.MatchString(suffix) {
+			return field.ErrorList{field.Invalid(suffixPath, suffix, "can only contain lowercase letters, numbers, hyphens and underscores")}
+		}
+	case RenameThenArchiveDeletionPolicy:
+		if suffix == "" {
+			return field.ErrorList{field.Required(suffixPath, fmt.Sprintf("is required with the %s deletion policy", RenameThenArchiveDeletionPolicy))}
+		}
+		if !channelNameRegex.MatchString(suffix) {
+			return field.ErrorList{field.Invalid(suffixPath, suffix, "can only contain lowercase letters, numbers, hyphens and underscores")}
+		}
+		if len(channel.Spec.Name)+len(suffix) > maxChannelNameLength {
+			return field.ErrorList{field.Invalid(suffixPath, suffix, fmt.Sprintf("channel name with archive suffix can not be longer than %d characters", maxChannelNameLength))}
+		}
+	default:
+		return field.ErrorList{field.NotSupported(field.NewPath("spec", "deletionPolicy"), channel.Spec.DeletionPolicy,
+			[]string{string(ArchiveDeletionPolicy), string(RetainDeletionPolicy), string(RenameThenArchiveDeletionPolicy)})}
+	}
+	return nil
+}
+
+func ValidateAdoption(channel *Channel) field.ErrorList {
+	adopt := channel.Spec.Adopt
+	if adopt == nil {
+		return nil
+	}
+
+	adoptPath := field.NewPath("spec", "adopt")
+	if adopt.ID == "" && adopt.Name == "" {
+		return field.ErrorList{field.Required(adoptPath, "either id or name of the channel to adopt must be specified")}
+	}
+	if adopt.ID != "" && adopt.Name != "" {
+		return field.ErrorList{field.Forbidden(adoptPath.Child("name"), "only one of id or name of the channel to adopt can be specified")}
+	}
+	return nil
+}
+
+func ValidateResyncPeriod(channel *Channel) field.ErrorList {
+	if channel.Spec.ResyncPeriod != nil && channel.Spec.ResyncPeriod.Duration < 0 {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "resyncPeriod"), channel.Spec.ResyncPeriod.Duration.String(), "can not be negative")}
+	}
+	return nil
+}
+
+func ValidateMembersFrom(channel *Channel) field.ErrorList {
+	membersFrom := channel.Spec.MembersFrom
+	if membersFrom == nil {
+		return nil
+	}
+
+	membersFromPath := field.NewPath("spec", "membersFrom")
+	if membersFrom.RoleBindings == nil && membersFrom.ClusterRoleBindings == nil {
+		return field.ErrorList{field.Required(membersFromPath, "either roleBindings or clusterRoleBindings must be specified")}
+	}
+
+	// Selecting all ClusterRoleBindings would add every user of the cluster
+	clusterRoleBindings := membersFrom.ClusterRoleBindings
+	if clusterRoleBindings != nil && len(clusterRoleBindings.Names) == 0 && len(clusterRoleBindings.Roles) == 0 {
+		return field.ErrorList{field.Required(membersFromPath.Child("clusterRoleBindings"), "at least one name or role is required")}
+	}
+
+	var errs field.ErrorList
+	if membersFrom.RoleBindings != nil {
+		errs = append(errs, validateRoleKinds(membersFrom.RoleBindings.Roles, membersFromPath.Child("roleBindings", "roles"), "Role", "ClusterRole")...)
+	}
+	if clusterRoleBindings != nil {
+		errs = append(errs, validateRoleKinds(clusterRoleBindings.Roles, membersFromPath.Child("clusterRoleBindings", "roles"), "ClusterRole")...)
+	}
+	return errs
+}
+
+// validateRoleKinds checks that the roles prefixed with a kind use one of the kinds the bindings can refer to
+func validateRoleKinds(roles []string, rolesPath *field.Path, kinds ...string) field.ErrorList {
+	var errs field.ErrorList
+	for i, role := range roles {
+		separator := strings.Index(role, "/")
+		if separator < 0 {
+			continue
+		}
+		if !containsString(kinds, role[:separator]) || separator == len(role)-1 {
+			errs = append(errs, field.Invalid(rolesPath.Index(i), role, "must be a role name, optionally prefixed with "+strings.Join(kinds, "/ or ")+"/"))
+		}
+	}
+	return errs
+}
+
+func ValidatePostingPolicy(channel *Channel) field.ErrorList {
+	if channel.Spec.PostingPolicy == ManagersPostingPolicy && len(channel.Spec.Managers) == 0 {
+		return field.ErrorList{field.Required(field.NewPath("spec", "managers"), "posting policy 'Managers' requires at least one manager")}
 	}
 	return nil
 }
+
+func ValidateSharedWith(channel *Channel) field.ErrorList {
+	var errs field.ErrorList
+	emails := map[string]bool{}
+	for i, invitation := range channel.Spec.SharedWith {
+		emailPath := field.NewPath("spec", "sharedWith").Index(i).Child("email")
+		if !isEmail(invitation.Email) {
+			errs = append(errs, field.Invalid(emailPath, invitation.Email, "must be an email address"))
+		}
+		if emails[strings.ToLower(invitation.Email)] {
+			errs = append(errs, field.Duplicate(emailPath, invitation.Email))
+		}
+		emails[strings.ToLower(invitation.Email)] = true
+	}
+	return errs
+}
+
+func ValidateBookmarks(channel *Channel) field.ErrorList {
+	var errs field.ErrorList
+	titles := map[string]bool{}
+	for i, bookmark := range channel.Spec.Bookmarks {
+		if titles[bookmark.Title] {
+			errs = append(errs, field.Duplicate(field.NewPath("spec", "bookmarks").Index(i).Child("title"), bookmark.Title))
+		}
+		titles[bookmark.Title] = true
+	}
+	return errs
+}
+
+func ValidatePinnedMessages(channel *Channel) field.ErrorList {
+	var errs field.ErrorList
+	texts := map[string]bool{}
+	for i, message := range channel.Spec.PinnedMessages {
+		if texts[message.Text] {
+			errs = append(errs, field.Duplicate(field.NewPath("spec", "pinnedMessages").Index(i).Child("text"), message.Text))
+		}
+		texts[message.Text] = true
+	}
+	return errs
+}
+
+func ValidateWelcomeMessage(channel *Channel) field.ErrorList {
+	welcomeMessage := channel.Spec.WelcomeMessage
+	if welcomeMessage == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	welcomeMessagePath := field.NewPath("spec", "welcomeMessage")
+	_, err := template.New("text").Parse(welcomeMessage.Text)
+	if err != nil {
+		errs = append(errs, field.Invalid(welcomeMessagePath.Child("text"), welcomeMessage.Text, fmt.Sprintf("invalid template: %s", err.Error())))
+	}
+
+	_, err = template.New("blocks").Parse(welcomeMessage.Blocks)
+	if err != nil {
+		errs = append(errs, field.Invalid(welcomeMessagePath.Child("blocks"), welcomeMessage.Blocks, fmt.Sprintf("invalid template: %s", err.Error())))
+	}
+	return errs
+}
+
+func ValidateNotifications(channel *Channel) field.ErrorList {
+	notifications := channel.Spec.Notifications
+	if notifications == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	notificationsPath := field.NewPath("spec", "notifications")
+	if notifications.ThrottlePeriod != nil && notifications.ThrottlePeriod.Duration < 0 {
+		errs = append(errs, field.Invalid(notificationsPath.Child("throttlePeriod"), notifications.ThrottlePeriod.Duration.String(), "can not be negative"))
+	}
+
+	if notifications.Events != nil {
+		for i, eventType := range notifications.Events.Types {
+			if eventType != corev1.EventTypeNormal && eventType != corev1.EventTypeWarning {
+				errs = append(errs, field.NotSupported(notificationsPath.Child("events", "types").Index(i), eventType,
+					[]string{corev1.EventTypeNormal, corev1.EventTypeWarning}))
+			}
+		}
+	}
+
+	if notifications.PodFailures != nil {
+		_, err := metav1.LabelSelectorAsSelector(notifications.PodFailures.Selector)
+		if err != nil {
+			errs = append(errs, field.Invalid(notificationsPath.Child("podFailures", "selector"), notifications.PodFailures.Selector, err.Error()))
+		}
+	}
+
+	if notifications.DeploymentRollouts != nil {
+		_, err := metav1.LabelSelectorAsSelector(notifications.DeploymentRollouts.Selector)
+		if err != nil {
+			errs = append(errs, field.Invalid(notificationsPath.Child("deploymentRollouts", "selector"), notifications.DeploymentRollouts.Selector, err.Error()))
+		}
+	}
+	return errs
+}
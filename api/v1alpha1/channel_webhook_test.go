@@ -0,0 +1,426 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stakater/slack-operator/pkg/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newChannel() *Channel {
+	return &Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-channel",
+			Namespace: "test",
+		},
+		Spec: ChannelSpec{
+			Name:  "my-channel",
+			Users: []string{"iamuser@slack.com"},
+		},
+	}
+}
+
+// invalidFields returns the field paths of the Invalid error returned by the validation
+func invalidFields(err error) []string {
+	Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected an Invalid error, got %v", err)
+
+	var fields []string
+	for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	return fields
+}
+
+var _ = Describe("Channel webhook", func() {
+
+	var channel *Channel
+
+	BeforeEach(func() {
+		channel = newChannel()
+	})
+
+	Describe("Defaulting", func() {
+		AfterEach(func() {
+			config.Set(config.Default())
+		})
+
+		It("should default the policies from the operator config", func() {
+			channel.Default()
+			Expect(channel.Spec.DeletionPolicy).To(Equal(ArchiveDeletionPolicy))
+			Expect(channel.Spec.ArchiveSuffix).To(BeEmpty())
+			Expect(channel.Spec.DriftPolicy).To(Equal(EnforceDriftPolicy))
+			Expect(channel.Spec.MembershipPolicy).To(Equal(AuthoritativeMembershipPolicy))
+		})
+
+		It("should keep the policies set on the channel", func() {
+			channel.Spec.DeletionPolicy = RetainDeletionPolicy
+			channel.Spec.DriftPolicy = ReportOnlyDriftPolicy
+			channel.Spec.MembershipPolicy = AdditiveMembershipPolicy
+			channel.Default()
+			Expect(channel.Spec.DeletionPolicy).To(Equal(RetainDeletionPolicy))
+			Expect(channel.Spec.DriftPolicy).To(Equal(ReportOnlyDriftPolicy))
+			Expect(channel.Spec.MembershipPolicy).To(Equal(AdditiveMembershipPolicy))
+		})
+
+		It("should validate the archive suffix of the operator config against the channel name", func() {
+			operatorConfig := config.Default()
+			operatorConfig.ChannelDefaults.DeletionPolicy = string(RenameThenArchiveDeletionPolicy)
+			operatorConfig.ChannelDefaults.ArchiveSuffix = "-archived"
+			config.Set(operatorConfig)
+
+			channel.Spec.Name = strings.Repeat("a", 75)
+			channel.Default()
+			Expect(channel.Spec.DeletionPolicy).To(Equal(RenameThenArchiveDeletionPolicy))
+			Expect(channel.Spec.ArchiveSuffix).To(Equal("-archived"))
+			Expect(invalidFields(channel.ValidateCreate())).To(ConsistOf("spec.archiveSuffix"))
+		})
+	})
+
+	Describe("Deriving the name", func() {
+		useNameTemplate := func(nameTemplate string) {
+			operatorConfig := config.Default()
+			operatorConfig.ChannelDefaults.NameTemplate = nameTemplate
+			config.Set(operatorConfig)
+		}
+
+		BeforeEach(func() {
+			channel.Spec.Name = ""
+			channel.Labels = map[string]string{"team": "SRE"}
+		})
+
+		AfterEach(func() {
+			config.Set(config.Default())
+		})
+
+		It("should derive the name from the name template", func() {
+			useNameTemplate("{{ .Namespace }}-{{ .Labels.team }}")
+			channel.Default()
+			Expect(channel.Spec.Name).To(Equal("test-sre"))
+			Expect(channel.ValidateCreate()).To(Succeed())
+		})
+
+		It("should keep the name if it is set", func() {
+			useNameTemplate("{{ .Namespace }}-{{ .Labels.team }}")
+			channel.Spec.Name = "my-channel"
+			channel.Default()
+			Expect(channel.Spec.Name).To(Equal("my-channel"))
+		})
+
+		It("should replace illegal characters and truncate the name", func() {
+			useNameTemplate("{{ .Labels.team }} Alerts & {{ .Labels.env }}!")
+			channel.Labels["env"] = strings.Repeat("x", 100)
+			channel.Default()
+			Expect(channel.Spec.Name).To(HavePrefix("sre-alerts-xxx"))
+			Expect(channel.Spec.Name).To(HaveLen(80))
+		})
+
+		It("should reject the channel if the template refers to a missing label", func() {
+			useNameTemplate("{{ .Namespace }}-{{ .Labels.product }}")
+			channel.Default()
+			Expect(channel.Spec.Name).To(BeEmpty())
+			Expect(channel.ValidateCreate()).To(MatchError(ContainSubstring("name template")))
+		})
+
+		It("should require the name without a name template", func() {
+			channel.Default()
+			Expect(channel.Spec.Name).To(BeEmpty())
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("Selecting bindings", func() {
+		It("should select bindings to a Role or a ClusterRole with the name", func() {
+			selector := &BindingSelector{Roles: []string{"edit"}}
+			Expect(selector.Matches("team-a", "Role", "edit")).To(BeTrue())
+			Expect(selector.Matches("team-a", "ClusterRole", "edit")).To(BeTrue())
+			Expect(selector.Matches("team-a", "ClusterRole", "admin")).To(BeFalse())
+		})
+
+		It("should only select bindings to a role of the kind the name is prefixed with", func() {
+			selector := &BindingSelector{Roles: []string{"ClusterRole/edit", "Role/deployer"}}
+			Expect(selector.Matches("team-a", "ClusterRole", "edit")).To(BeTrue())
+			Expect(selector.Matches("team-a", "Role", "edit")).To(BeFalse())
+			Expect(selector.Matches("team-a", "Role", "deployer")).To(BeTrue())
+			Expect(selector.Matches("team-a", "ClusterRole", "deployer")).To(BeFalse())
+		})
+
+		It("should select bindings by name and role", func() {
+			selector := &BindingSelector{Names: []string{"team-a"}, Roles: []string{"Role/edit"}}
+			Expect(selector.Matches("team-a", "Role", "edit")).To(BeTrue())
+			Expect(selector.Matches("team-b", "Role", "edit")).To(BeFalse())
+			Expect((&BindingSelector{}).Matches("team-b", "ClusterRole", "view")).To(BeTrue())
+		})
+	})
+
+	Describe("Validating users", func() {
+		It("should accept user groups without users", func() {
+			channel.Spec.Users = nil
+			channel.Spec.UserGroups = []string{"@sre-oncall"}
+			Expect(channel.ValidateCreate()).To(Succeed())
+		})
+
+		It("should accept members from role bindings without users", func() {
+			channel.Spec.Users = nil
+			channel.Spec.MembersFrom = &MembersFrom{RoleBindings: &BindingSelector{}}
+			Expect(channel.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject members from all cluster role bindings", func() {
+			channel.Spec.MembersFrom = &MembersFrom{ClusterRoleBindings: &BindingSelector{}}
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+
+			channel.Spec.MembersFrom.ClusterRoleBindings.Roles = []string{"cluster-admin"}
+			Expect(channel.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject roles prefixed with a kind the bindings can't refer to", func() {
+			channel.Spec.MembersFrom = &MembersFrom{
+				RoleBindings:        &BindingSelector{Roles: []string{"Role/edit", "ClusterRole/admin"}},
+				ClusterRoleBindings: &BindingSelector{Roles: []string{"ClusterRole/cluster-admin"}},
+			}
+			Expect(channel.ValidateCreate()).To(Succeed())
+
+			channel.Spec.MembersFrom.ClusterRoleBindings.Roles = []string{"Role/cluster-admin"}
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+
+			channel.Spec.MembersFrom.ClusterRoleBindings.Roles = []string{"ClusterRole/"}
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+
+		It("should accept a channel without users if its membership is ignored", func() {
+			channel.Spec.Users = nil
+			channel.Spec.MembershipPolicy = IgnoreMembershipPolicy
+			Expect(channel.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject a channel without users or user groups", func() {
+			channel.Spec.Users = nil
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("Validating name, topic and description", func() {
+		It("should reject names with characters slack doesn't allow", func() {
+			channel.Spec.Name = "My Channel"
+			Expect(invalidFields(channel.ValidateCreate())).To(ConsistOf("spec.name"))
+		})
+
+		It("should reject names longer than 80 characters", func() {
+			channel.Spec.Name = strings.Repeat("a", 81)
+			Expect(invalidFields(channel.ValidateCreate())).To(ConsistOf("spec.name"))
+		})
+
+		It("should reject topics and descriptions longer than 250 characters", func() {
+			channel.Spec.Topic = strings.Repeat("a", 251)
+			channel.Spec.Description = strings.Repeat("a", 251)
+			Expect(invalidFields(channel.ValidateCreate())).To(ConsistOf("spec.topic", "spec.description"))
+
+			channel.Spec.Topic = strings.Repeat("ä", 250)
+			channel.Spec.Description = ""
+			Expect(channel.ValidateCreate()).To(Succeed())
+		})
+	})
+
+	Describe("Validating emails", func() {
+		It("should reject users that are not email addresses", func() {
+			channel.Spec.Users = []string{"iamuser@slack.com", "U0G9QF9C6", "I Am User <iamuser@slack.com>"}
+			Expect(invalidFields(channel.ValidateCreate())).To(ConsistOf("spec.users[1]", "spec.users[2]"))
+		})
+
+		It("should reject duplicate users", func() {
+			channel.Spec.Users = []string{"iamuser@slack.com", "IAmUser@slack.com"}
+			Expect(invalidFields(channel.ValidateCreate())).To(ConsistOf("spec.users[1]"))
+		})
+
+		It("should reject managers and shared invitations that are not email addresses", func() {
+			channel.Spec.Managers = []string{"manager"}
+			channel.Spec.SharedWith = []SharedInvitation{{Email: "vendor.example.com"}}
+			Expect(invalidFields(channel.ValidateCreate())).To(ConsistOf("spec.managers[0]", "spec.sharedWith[0].email"))
+		})
+
+		It("should report all invalid fields", func() {
+			channel.Spec.Name = "My Channel"
+			channel.Spec.Users = []string{"iamuser"}
+			channel.Spec.Adopt = &ChannelAdoption{}
+			Expect(invalidFields(channel.ValidateCreate())).To(ConsistOf("spec.name", "spec.users[0]", "spec.adopt"))
+		})
+	})
+
+	Describe("Validating unique names", func() {
+		var existing *Channel
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(AddToScheme(scheme)).To(Succeed())
+
+			existing = newChannel()
+			existing.Name = "sre"
+			existing.Namespace = "sre"
+			channelReader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+		})
+
+		AfterEach(func() {
+			channelReader = nil
+		})
+
+		It("should reject a second Channel for the same slack channel", func() {
+			Expect(invalidFields(channel.ValidateCreate())).To(ConsistOf("spec.name"))
+		})
+
+		It("should accept a Channel for a slack channel of another workspace", func() {
+			channel.Spec.Workspace = "business-unit"
+			Expect(channel.ValidateCreate()).To(Succeed())
+		})
+
+		It("should accept updating the Channel of the slack channel", func() {
+			oldChannel := existing.DeepCopy()
+			existing.Spec.Topic = "Site reliability"
+			Expect(existing.ValidateUpdate(oldChannel)).To(Succeed())
+		})
+
+		It("should reject renaming a slack channel to the name of another", func() {
+			oldChannel := channel.DeepCopy()
+			oldChannel.Spec.Name = "other-channel"
+			Expect(invalidFields(channel.ValidateUpdate(oldChannel))).To(ConsistOf("spec.name"))
+		})
+	})
+
+	Describe("Validating adoption", func() {
+		It("should accept adoption by ID", func() {
+			channel.Spec.Adopt = &ChannelAdoption{ID: "C0EAQDV4Z"}
+			Expect(channel.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject adoption without ID or name", func() {
+			channel.Spec.Adopt = &ChannelAdoption{}
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+
+		It("should reject adoption with both ID and name", func() {
+			channel.Spec.Adopt = &ChannelAdoption{ID: "C0EAQDV4Z", Name: "my-channel"}
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("Validating deletion policy", func() {
+		It("should require archive suffix with RenameThenArchive", func() {
+			channel.Spec.DeletionPolicy = RenameThenArchiveDeletionPolicy
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+
+			channel.Spec.ArchiveSuffix = "-archived"
+			Expect(channel.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject archive suffix with other policies", func() {
+			channel.Spec.DeletionPolicy = RetainDeletionPolicy
+			channel.Spec.ArchiveSuffix = "-archived"
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+
+		It("should accept archive suffix without a policy for the operator default", func() {
+			channel.Spec.ArchiveSuffix = "-archived"
+			Expect(channel.ValidateCreate()).To(Succeed())
+		})
+
+		It("should reject archive suffix with illegal characters", func() {
+			channel.Spec.DeletionPolicy = RenameThenArchiveDeletionPolicy
+			channel.Spec.ArchiveSuffix = " Archived!"
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("Validating posting policy", func() {
+		It("should require managers with the Managers posting policy", func() {
+			channel.Spec.PostingPolicy = ManagersPostingPolicy
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+
+			channel.Spec.Managers = []string{"manager@slack.com"}
+			Expect(channel.ValidateCreate()).To(Succeed())
+		})
+	})
+
+	Describe("Validating shared invitations", func() {
+		It("should reject inviting the same email twice", func() {
+			channel.Spec.SharedWith = []SharedInvitation{{Email: "vendor@example.com"}, {Email: "vendor@example.com", TeamID: "T0VENDOR1"}}
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("Validating bookmarks and pinned messages", func() {
+		It("should reject bookmarks with the same title", func() {
+			channel.Spec.Bookmarks = []Bookmark{{Title: "Runbook", Link: "https://runbooks.example.com"}, {Title: "Runbook", Link: "https://wiki.example.com"}}
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+
+		It("should reject pinned messages with the same text", func() {
+			channel.Spec.PinnedMessages = []PinnedMessage{{Text: "Read the runbook"}, {Text: "Read the runbook"}}
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("Validating welcome message", func() {
+		It("should reject invalid templates", func() {
+			channel.Spec.WelcomeMessage = &WelcomeMessage{Text: "Welcome to {{ .Spec.Name"}
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+
+		It("should accept templates over the channel and namespace labels", func() {
+			channel.Spec.WelcomeMessage = &WelcomeMessage{
+				Text:   "Welcome to {{ .Spec.Name }}",
+				Blocks: `[{"type": "section", "text": {"type": "mrkdwn", "text": "Owned by {{ .NamespaceLabels.team }}"}}]`,
+			}
+			Expect(channel.ValidateCreate()).To(Succeed())
+		})
+	})
+
+	Describe("Validating notifications", func() {
+		It("should reject invalid event types", func() {
+			channel.Spec.Notifications = &Notifications{Events: &EventNotifications{Types: []string{"Error"}}}
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+
+		It("should reject invalid selectors", func() {
+			channel.Spec.Notifications = &Notifications{PodFailures: &PodFailureNotifications{
+				Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Matches"}}},
+			}}
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("Validating resync period", func() {
+		It("should reject negative resync period", func() {
+			channel.Spec.ResyncPeriod = &metav1.Duration{Duration: -time.Minute}
+			Expect(channel.ValidateCreate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("Validating workspace", func() {
+		It("should reject changing the workspace", func() {
+			oldChannel := channel.DeepCopy()
+			channel.Spec.Workspace = "business-unit"
+			Expect(channel.ValidateUpdate(oldChannel)).ToNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,93 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MessageSpec defines the desired state of Message
+type MessageSpec struct {
+	// Name of the Channel in the namespace of the Message to post it in
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Channel string `json:"channel"`
+
+	// Text of the message, which is the fallback shown in notifications if there are blocks. Either text or blocks
+	// are required
+	// +optional
+	Text string `json:"text,omitempty"`
+
+	// Block Kit blocks of the message as a JSON array
+	// +optional
+	Blocks string `json:"blocks,omitempty"`
+}
+
+// MessageStatus defines the observed state of Message
+type MessageStatus struct {
+	// ID of the slack channel the message was posted in
+	// +optional
+	ChannelID string `json:"channelID,omitempty"`
+
+	// Timestamp of the message on slack
+	// +optional
+	Timestamp string `json:"timestamp,omitempty"`
+
+	// Generation of the Message spec that was last posted
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Channel",type=string,JSONPath=`.spec.channel`
+// +kubebuilder:printcolumn:name="Timestamp",type=string,JSONPath=`.status.timestamp`
+
+// Message is the Schema for the messages API
+type Message struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MessageSpec   `json:"spec,omitempty"`
+	Status MessageStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MessageList contains a list of Message
+type MessageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Message `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Message{}, &MessageList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making Message ConditionsStatusAware
+func (message *Message) GetReconcileStatus() []metav1.Condition {
+	return message.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making Message ConditionsStatusAware
+func (message *Message) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	message.Status.Conditions = reconcileStatus
+}
@@ -0,0 +1,90 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SlackWorkspaceSpec defines the desired state of SlackWorkspace
+type SlackWorkspaceSpec struct {
+	// Secret in the namespace of the SlackWorkspace containing the API token of the slack workspace
+	// +required
+	TokenSecretRef SecretKeyReference `json:"tokenSecretRef"`
+}
+
+// SecretKeyReference references a key of a secret
+type SecretKeyReference struct {
+	// Name of the secret
+	// +required
+	Name string `json:"name"`
+
+	// Key of the secret containing the value
+	// +kubebuilder:default=APIToken
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// SlackWorkspaceStatus defines the observed state of SlackWorkspace
+type SlackWorkspaceStatus struct {
+	// ID of the slack workspace the token belongs to
+	// +optional
+	TeamID string `json:"teamID,omitempty"`
+
+	// Name of the slack workspace the token belongs to
+	// +optional
+	Team string `json:"team,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Team",type=string,JSONPath=`.status.team`
+
+// SlackWorkspace is the Schema for the slackworkspaces API
+type SlackWorkspace struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SlackWorkspaceSpec   `json:"spec,omitempty"`
+	Status SlackWorkspaceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SlackWorkspaceList contains a list of SlackWorkspace
+type SlackWorkspaceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SlackWorkspace `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SlackWorkspace{}, &SlackWorkspaceList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making SlackWorkspace ConditionsStatusAware
+func (workspace *SlackWorkspace) GetReconcileStatus() []metav1.Condition {
+	return workspace.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making SlackWorkspace ConditionsStatusAware
+func (workspace *SlackWorkspace) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	workspace.Status.Conditions = reconcileStatus
+}
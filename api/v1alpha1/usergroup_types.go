@@ -0,0 +1,98 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UserGroupSpec defines the desired state of UserGroup
+type UserGroupSpec struct {
+	// Handle of the slack user group, used to mention it without the @
+	// +kubebuilder:validation:Pattern=`^[a-z0-9._-]+$`
+	// +required
+	Handle string `json:"handle"`
+
+	// Name of the slack user group
+	// +required
+	Name string `json:"name"`
+
+	// Description of the slack user group
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// List of emails of the members of the user group
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	Users []string `json:"users"`
+
+	// Names of the Channels in the namespace of the UserGroup which new members of the user group join by default
+	// +optional
+	Channels []string `json:"channels,omitempty"`
+
+	// Adopt an existing slack user group with the same handle instead of failing. Its members are replaced by the
+	// users, and it is disabled when the UserGroup is deleted
+	// +optional
+	Adopt bool `json:"adopt,omitempty"`
+}
+
+// UserGroupStatus defines the observed state of UserGroup
+type UserGroupStatus struct {
+	// ID of the slack user group
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Handle",type=string,JSONPath=`.spec.handle`
+// +kubebuilder:printcolumn:name="ID",type=string,JSONPath=`.status.id`
+
+// UserGroup is the Schema for the usergroups API
+type UserGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UserGroupSpec   `json:"spec,omitempty"`
+	Status UserGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UserGroupList contains a list of UserGroup
+type UserGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []UserGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&UserGroup{}, &UserGroupList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making UserGroup ConditionsStatusAware
+func (userGroup *UserGroup) GetReconcileStatus() []metav1.Condition {
+	return userGroup.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making UserGroup ConditionsStatusAware
+func (userGroup *UserGroup) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	userGroup.Status.Conditions = reconcileStatus
+}
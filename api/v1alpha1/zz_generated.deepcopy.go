@@ -25,6 +25,61 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingSelector) DeepCopyInto(out *BindingSelector) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingSelector.
+func (in *BindingSelector) DeepCopy() *BindingSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Bookmark) DeepCopyInto(out *Bookmark) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bookmark.
+func (in *Bookmark) DeepCopy() *Bookmark {
+	if in == nil {
+		return nil
+	}
+	out := new(Bookmark)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BookmarkStatus) DeepCopyInto(out *BookmarkStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BookmarkStatus.
+func (in *BookmarkStatus) DeepCopy() *BookmarkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BookmarkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Channel) DeepCopyInto(out *Channel) {
 	*out = *in
@@ -52,6 +107,61 @@ func (in *Channel) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelAdoption) DeepCopyInto(out *ChannelAdoption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelAdoption.
+func (in *ChannelAdoption) DeepCopy() *ChannelAdoption {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelAdoption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelDrift) DeepCopyInto(out *ChannelDrift) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(FieldDrift)
+		**out = **in
+	}
+	if in.Topic != nil {
+		in, out := &in.Topic, &out.Topic
+		*out = new(FieldDrift)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(FieldDrift)
+		**out = **in
+	}
+	if in.MissingUsers != nil {
+		in, out := &in.MissingUsers, &out.MissingUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraUsers != nil {
+		in, out := &in.ExtraUsers, &out.ExtraUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelDrift.
+func (in *ChannelDrift) DeepCopy() *ChannelDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChannelList) DeepCopyInto(out *ChannelList) {
 	*out = *in
@@ -92,6 +202,56 @@ func (in *ChannelSpec) DeepCopyInto(out *ChannelSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.UserGroups != nil {
+		in, out := &in.UserGroups, &out.UserGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MembersFrom != nil {
+		in, out := &in.MembersFrom, &out.MembersFrom
+		*out = new(MembersFrom)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Adopt != nil {
+		in, out := &in.Adopt, &out.Adopt
+		*out = new(ChannelAdoption)
+		**out = **in
+	}
+	if in.Managers != nil {
+		in, out := &in.Managers, &out.Managers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SharedWith != nil {
+		in, out := &in.SharedWith, &out.SharedWith
+		*out = make([]SharedInvitation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bookmarks != nil {
+		in, out := &in.Bookmarks, &out.Bookmarks
+		*out = make([]Bookmark, len(*in))
+		copy(*out, *in)
+	}
+	if in.PinnedMessages != nil {
+		in, out := &in.PinnedMessages, &out.PinnedMessages
+		*out = make([]PinnedMessage, len(*in))
+		copy(*out, *in)
+	}
+	if in.WelcomeMessage != nil {
+		in, out := &in.WelcomeMessage, &out.WelcomeMessage
+		*out = new(WelcomeMessage)
+		**out = **in
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(Notifications)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelSpec.
@@ -107,6 +267,41 @@ func (in *ChannelSpec) DeepCopy() *ChannelSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChannelStatus) DeepCopyInto(out *ChannelStatus) {
 	*out = *in
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(ChannelDrift)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]MemberStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.SharedInvitations != nil {
+		in, out := &in.SharedInvitations, &out.SharedInvitations
+		*out = make([]SharedInvitationStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bookmarks != nil {
+		in, out := &in.Bookmarks, &out.Bookmarks
+		*out = make([]BookmarkStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.PinnedMessages != nil {
+		in, out := &in.PinnedMessages, &out.PinnedMessages
+		*out = make([]PinnedMessageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.WelcomeMessage != nil {
+		in, out := &in.WelcomeMessage, &out.WelcomeMessage
+		*out = new(WelcomeMessageStatus)
+		**out = **in
+	}
+	if in.Managers != nil {
+		in, out := &in.Managers, &out.Managers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -125,3 +320,567 @@ func (in *ChannelStatus) DeepCopy() *ChannelStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentRolloutNotifications) DeepCopyInto(out *DeploymentRolloutNotifications) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentRolloutNotifications.
+func (in *DeploymentRolloutNotifications) DeepCopy() *DeploymentRolloutNotifications {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentRolloutNotifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventNotifications) DeepCopyInto(out *EventNotifications) {
+	*out = *in
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventNotifications.
+func (in *EventNotifications) DeepCopy() *EventNotifications {
+	if in == nil {
+		return nil
+	}
+	out := new(EventNotifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldDrift) DeepCopyInto(out *FieldDrift) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldDrift.
+func (in *FieldDrift) DeepCopy() *FieldDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberStatus) DeepCopyInto(out *MemberStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberStatus.
+func (in *MemberStatus) DeepCopy() *MemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MembersFrom) DeepCopyInto(out *MembersFrom) {
+	*out = *in
+	if in.RoleBindings != nil {
+		in, out := &in.RoleBindings, &out.RoleBindings
+		*out = new(BindingSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterRoleBindings != nil {
+		in, out := &in.ClusterRoleBindings, &out.ClusterRoleBindings
+		*out = new(BindingSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MembersFrom.
+func (in *MembersFrom) DeepCopy() *MembersFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(MembersFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Message) DeepCopyInto(out *Message) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Message.
+func (in *Message) DeepCopy() *Message {
+	if in == nil {
+		return nil
+	}
+	out := new(Message)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Message) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MessageList) DeepCopyInto(out *MessageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Message, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MessageList.
+func (in *MessageList) DeepCopy() *MessageList {
+	if in == nil {
+		return nil
+	}
+	out := new(MessageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MessageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MessageSpec) DeepCopyInto(out *MessageSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MessageSpec.
+func (in *MessageSpec) DeepCopy() *MessageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MessageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MessageStatus) DeepCopyInto(out *MessageStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MessageStatus.
+func (in *MessageStatus) DeepCopy() *MessageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MessageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Notifications) DeepCopyInto(out *Notifications) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = new(EventNotifications)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodFailures != nil {
+		in, out := &in.PodFailures, &out.PodFailures
+		*out = new(PodFailureNotifications)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeploymentRollouts != nil {
+		in, out := &in.DeploymentRollouts, &out.DeploymentRollouts
+		*out = new(DeploymentRolloutNotifications)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ThrottlePeriod != nil {
+		in, out := &in.ThrottlePeriod, &out.ThrottlePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notifications.
+func (in *Notifications) DeepCopy() *Notifications {
+	if in == nil {
+		return nil
+	}
+	out := new(Notifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PinnedMessage) DeepCopyInto(out *PinnedMessage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PinnedMessage.
+func (in *PinnedMessage) DeepCopy() *PinnedMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(PinnedMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PinnedMessageStatus) DeepCopyInto(out *PinnedMessageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PinnedMessageStatus.
+func (in *PinnedMessageStatus) DeepCopy() *PinnedMessageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PinnedMessageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodFailureNotifications) DeepCopyInto(out *PodFailureNotifications) {
+	*out = *in
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodFailureNotifications.
+func (in *PodFailureNotifications) DeepCopy() *PodFailureNotifications {
+	if in == nil {
+		return nil
+	}
+	out := new(PodFailureNotifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyReference) DeepCopyInto(out *SecretKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyReference.
+func (in *SecretKeyReference) DeepCopy() *SecretKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedInvitation) DeepCopyInto(out *SharedInvitation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedInvitation.
+func (in *SharedInvitation) DeepCopy() *SharedInvitation {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedInvitation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedInvitationStatus) DeepCopyInto(out *SharedInvitationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedInvitationStatus.
+func (in *SharedInvitationStatus) DeepCopy() *SharedInvitationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedInvitationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackWorkspace) DeepCopyInto(out *SlackWorkspace) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackWorkspace.
+func (in *SlackWorkspace) DeepCopy() *SlackWorkspace {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackWorkspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SlackWorkspace) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackWorkspaceList) DeepCopyInto(out *SlackWorkspaceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SlackWorkspace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackWorkspaceList.
+func (in *SlackWorkspaceList) DeepCopy() *SlackWorkspaceList {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackWorkspaceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SlackWorkspaceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackWorkspaceSpec) DeepCopyInto(out *SlackWorkspaceSpec) {
+	*out = *in
+	out.TokenSecretRef = in.TokenSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackWorkspaceSpec.
+func (in *SlackWorkspaceSpec) DeepCopy() *SlackWorkspaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackWorkspaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackWorkspaceStatus) DeepCopyInto(out *SlackWorkspaceStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackWorkspaceStatus.
+func (in *SlackWorkspaceStatus) DeepCopy() *SlackWorkspaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackWorkspaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserGroup) DeepCopyInto(out *UserGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserGroup.
+func (in *UserGroup) DeepCopy() *UserGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(UserGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UserGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserGroupList) DeepCopyInto(out *UserGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]UserGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserGroupList.
+func (in *UserGroupList) DeepCopy() *UserGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(UserGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UserGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserGroupSpec) DeepCopyInto(out *UserGroupSpec) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserGroupSpec.
+func (in *UserGroupSpec) DeepCopy() *UserGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UserGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserGroupStatus) DeepCopyInto(out *UserGroupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserGroupStatus.
+func (in *UserGroupStatus) DeepCopy() *UserGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UserGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WelcomeMessage) DeepCopyInto(out *WelcomeMessage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WelcomeMessage.
+func (in *WelcomeMessage) DeepCopy() *WelcomeMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(WelcomeMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WelcomeMessageStatus) DeepCopyInto(out *WelcomeMessageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WelcomeMessageStatus.
+func (in *WelcomeMessageStatus) DeepCopy() *WelcomeMessageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WelcomeMessageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
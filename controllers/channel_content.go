@@ -0,0 +1,355 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// welcomeMessageData is what the templates of the welcome message are rendered with
+type welcomeMessageData struct {
+	slackv1alpha1.Channel
+
+	// Labels of the namespace of the Channel
+	NamespaceLabels map[string]string
+}
+
+// syncWelcomeMessage posts the welcome message once and records it in the status right away, returning whether the
+// status changed. The message is edited when its rendered text or blocks change, and deleted when it is removed from
+// the spec. Adopted channels already have members and don't get a welcome message.
+func (r *ChannelReconciler) syncWelcomeMessage(ctx context.Context, channel *slackv1alpha1.Channel, slackService slack.Service) (bool, error) {
+	channelID := channel.Status.ID
+	log := r.Log.WithValues("channelID", channelID)
+	status := channel.Status.WelcomeMessage
+
+	if status == nil && channel.Status.Adopted {
+		return false, nil
+	}
+
+	if channel.Spec.WelcomeMessage == nil {
+		if status == nil {
+			return false, nil
+		}
+
+		log.Info("Deleting welcome message", "timestamp", status.Timestamp)
+		err := slackService.DeleteMessage(channelID, status.Timestamp)
+		if err != nil {
+			return false, err
+		}
+		channel.Status.WelcomeMessage = nil
+		return true, nil
+	}
+
+	text, blocks, err := r.renderWelcomeMessage(ctx, channel)
+	if err != nil {
+		return false, err
+	}
+	checksum := fmt.Sprintf("%x", sha256.Sum256([]byte(text+"\n"+blocks)))
+
+	if status != nil {
+		if status.Checksum == checksum {
+			return false, nil
+		}
+
+		log.Info("Updating welcome message", "timestamp", status.Timestamp)
+		err := slackService.UpdateMessage(channelID, status.Timestamp, text, blocks)
+		if err == nil {
+			status.Checksum = checksum
+			return true, nil
+		}
+		// A welcome message deleted on slack is posted again
+		if err.Error() != "message_not_found" {
+			return false, err
+		}
+	}
+
+	log.Info("Posting welcome message")
+	timestamp, err := slackService.PostMessage(channelID, text, blocks)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.recordStatus(ctx, channel, func() {
+		channel.Status.WelcomeMessage = &slackv1alpha1.WelcomeMessageStatus{Timestamp: timestamp, Checksum: checksum}
+	})
+	return err == nil, err
+}
+
+// renderWelcomeMessage renders the text and the blocks of the welcome message with the Channel and the labels of its
+// namespace. Missing labels are rendered as empty strings.
+func (r *ChannelReconciler) renderWelcomeMessage(ctx context.Context, channel *slackv1alpha1.Channel) (string, string, error) {
+	namespace := &corev1.Namespace{}
+	err := r.Get(ctx, types.NamespacedName{Name: channel.Namespace}, namespace)
+	if err != nil {
+		return "", "", err
+	}
+
+	data := welcomeMessageData{Channel: *channel, NamespaceLabels: namespace.Labels}
+
+	text, err := renderTemplate("text", channel.Spec.WelcomeMessage.Text, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	blocks, err := renderTemplate("blocks", channel.Spec.WelcomeMessage.Blocks, data)
+	if err != nil {
+		return "", "", err
+	}
+	return text, blocks, nil
+}
+
+// renderTemplate renders the template with the data
+func renderTemplate(name string, text string, data interface{}) (string, error) {
+	var rendered bytes.Buffer
+
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err == nil {
+		err = tmpl.Execute(&rendered, data)
+	}
+	if err != nil {
+		return "", fmt.Errorf("Error rendering welcome message %s: %s", name, err.Error())
+	}
+	return rendered.String(), nil
+}
+
+// syncBookmarks adds, edits and removes the bookmarks of the channel and records the ones that were added in the
+// status right away, returning whether the status changed. Bookmarks that weren't added by the operator are left
+// untouched.
+func (r *ChannelReconciler) syncBookmarks(ctx context.Context, channel *slackv1alpha1.Channel, slackService slack.Service) (bool, error) {
+	if len(channel.Spec.Bookmarks) == 0 && len(channel.Status.Bookmarks) == 0 {
+		return false, nil
+	}
+
+	channelID := channel.Status.ID
+	log := r.Log.WithValues("channelID", channelID)
+	changed := false
+
+	existingBookmarks, err := slackService.ListBookmarks(channelID)
+	if err != nil {
+		return false, err
+	}
+
+	existing := map[string]slack.Bookmark{}
+	for _, bookmark := range existingBookmarks {
+		existing[bookmark.ID] = bookmark
+	}
+
+	// Bookmarks removed on slack are forgotten and added again, the ones removed from the spec are removed from slack
+	var owned []slackv1alpha1.BookmarkStatus
+	for i, status := range channel.Status.Bookmarks {
+		if _, ok := existing[status.ID]; !ok {
+			changed = true
+			continue
+		}
+
+		if findBookmark(channel.Spec.Bookmarks, status.Title) == nil {
+			log.Info("Removing bookmark", "title", status.Title)
+			err := slackService.RemoveBookmark(channelID, status.ID)
+			if err != nil {
+				channel.Status.Bookmarks = append(owned, channel.Status.Bookmarks[i:]...)
+				return changed, err
+			}
+			changed = true
+			continue
+		}
+
+		owned = append(owned, status)
+	}
+	channel.Status.Bookmarks = owned
+
+	for _, bookmark := range channel.Spec.Bookmarks {
+		status := findBookmarkStatus(channel.Status.Bookmarks, bookmark.Title)
+
+		if status == nil {
+			log.Info("Adding bookmark", "title", bookmark.Title)
+			added, err := slackService.AddBookmark(channelID, slack.Bookmark{Title: bookmark.Title, Link: bookmark.Link, Emoji: bookmark.Emoji})
+			if err != nil {
+				return changed, err
+			}
+
+			err = r.recordStatus(ctx, channel, func() {
+				channel.Status.Bookmarks = append(channel.Status.Bookmarks, slackv1alpha1.BookmarkStatus{ID: added.ID, Title: bookmark.Title})
+			})
+			if err != nil {
+				return changed, err
+			}
+			changed = true
+			continue
+		}
+
+		current := existing[status.ID]
+		if current.Title != bookmark.Title || current.Link != bookmark.Link || current.Emoji != bookmark.Emoji {
+			log.Info("Editing bookmark", "title", bookmark.Title)
+			_, err := slackService.EditBookmark(channelID, slack.Bookmark{ID: status.ID, Title: bookmark.Title, Link: bookmark.Link, Emoji: bookmark.Emoji})
+			if err != nil {
+				return changed, err
+			}
+		}
+	}
+
+	return changed, nil
+}
+
+// syncPinnedMessages posts and pins the messages of the channel and records them in the status right away, returning
+// whether the status changed. Messages removed from the spec are unpinned and deleted, messages that weren't posted by
+// the operator are left untouched.
+func (r *ChannelReconciler) syncPinnedMessages(ctx context.Context, channel *slackv1alpha1.Channel, slackService slack.Service) (bool, error) {
+	if len(channel.Spec.PinnedMessages) == 0 && len(channel.Status.PinnedMessages) == 0 {
+		return false, nil
+	}
+
+	channelID := channel.Status.ID
+	log := r.Log.WithValues("channelID", channelID)
+	changed := false
+
+	pinned, err := slackService.ListPinnedMessages(channelID)
+	if err != nil {
+		return false, err
+	}
+
+	var owned []slackv1alpha1.PinnedMessageStatus
+	for i, status := range channel.Status.PinnedMessages {
+		keep, err := r.syncPinnedMessage(channel, slackService, status, pinned)
+		if err != nil {
+			channel.Status.PinnedMessages = append(owned, channel.Status.PinnedMessages[i:]...)
+			return changed, err
+		}
+
+		if keep {
+			owned = append(owned, status)
+		} else {
+			changed = true
+		}
+	}
+	channel.Status.PinnedMessages = owned
+
+	for _, message := range channel.Spec.PinnedMessages {
+		if findPinnedMessageStatus(channel.Status.PinnedMessages, message.Text) != nil {
+			continue
+		}
+
+		log.Info("Posting pinned message")
+		timestamp, err := slackService.PostMessage(channelID, message.Text, "")
+		if err != nil {
+			return changed, err
+		}
+
+		// The message is recorded before it is pinned, so that it is pinned on the next reconcile if pinning fails
+		err = r.recordStatus(ctx, channel, func() {
+			channel.Status.PinnedMessages = append(channel.Status.PinnedMessages, slackv1alpha1.PinnedMessageStatus{Timestamp: timestamp, Text: message.Text})
+		})
+		if err != nil {
+			return changed, err
+		}
+		changed = true
+
+		err = slackService.PinMessage(channelID, timestamp)
+		if err != nil {
+			return changed, err
+		}
+	}
+
+	return changed, nil
+}
+
+// syncPinnedMessage unpins and deletes a message posted by the operator if it was removed from the spec, otherwise it
+// pins the message again and restores its text if they were changed on slack. It returns false if the message is no
+// longer owned, either because it was removed or because it was deleted on slack and has to be posted again.
+func (r *ChannelReconciler) syncPinnedMessage(channel *slackv1alpha1.Channel, slackService slack.Service, status slackv1alpha1.PinnedMessageStatus, pinned map[string]string) (bool, error) {
+	channelID := channel.Status.ID
+	log := r.Log.WithValues("channelID", channelID, "timestamp", status.Timestamp)
+
+	if findPinnedMessage(channel.Spec.PinnedMessages, status.Text) == nil {
+		log.Info("Removing pinned message")
+		err := slackService.UnpinMessage(channelID, status.Timestamp)
+		if err != nil {
+			return false, err
+		}
+		return false, slackService.DeleteMessage(channelID, status.Timestamp)
+	}
+
+	text, ok := pinned[status.Timestamp]
+	if !ok {
+		log.Info("Pinning message again")
+		err := slackService.PinMessage(channelID, status.Timestamp)
+		if err != nil && err.Error() == "message_not_found" {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	if text != status.Text {
+		log.Info("Restoring text of pinned message")
+		err := slackService.UpdateMessage(channelID, status.Timestamp, status.Text, "")
+		if err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// findBookmark returns the bookmark of the spec with the title, or nil if there is none
+func findBookmark(bookmarks []slackv1alpha1.Bookmark, title string) *slackv1alpha1.Bookmark {
+	for i := range bookmarks {
+		if bookmarks[i].Title == title {
+			return &bookmarks[i]
+		}
+	}
+	return nil
+}
+
+// findBookmarkStatus returns the status of the bookmark with the title, or nil if it wasn't added
+func findBookmarkStatus(bookmarks []slackv1alpha1.BookmarkStatus, title string) *slackv1alpha1.BookmarkStatus {
+	for i := range bookmarks {
+		if bookmarks[i].Title == title {
+			return &bookmarks[i]
+		}
+	}
+	return nil
+}
+
+// findPinnedMessage returns the pinned message of the spec with the text, or nil if there is none
+func findPinnedMessage(messages []slackv1alpha1.PinnedMessage, text string) *slackv1alpha1.PinnedMessage {
+	for i := range messages {
+		if messages[i].Text == text {
+			return &messages[i]
+		}
+	}
+	return nil
+}
+
+// findPinnedMessageStatus returns the status of the message with the text, or nil if it wasn't posted
+func findPinnedMessageStatus(messages []slackv1alpha1.PinnedMessageStatus, text string) *slackv1alpha1.PinnedMessageStatus {
+	for i := range messages {
+		if messages[i].Text == text {
+			return &messages[i]
+		}
+	}
+	return nil
+}
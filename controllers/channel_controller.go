@@ -18,22 +18,41 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/go-logr/logr"
+	slackapi "github.com/slack-go/slack"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
 	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
 	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/config"
 	slack "github.com/stakater/slack-operator/pkg/slack"
 	pkgutil "github.com/stakater/slack-operator/pkg/util"
 )
 
 var (
 	channelFinalizer string = "slack.stakater.com/channel"
+
+	// channelWorkspaceField is the field index used to look up the Channels of a SlackWorkspace
+	channelWorkspaceField string = "spec.workspace"
+
+	// resyncJitterFactor spreads resyncs of Channels so that they don't all hit the slack API at once
+	resyncJitterFactor float64 = 0.1
 )
 
 // ChannelReconciler reconciles a Channel object
@@ -42,11 +61,24 @@ type ChannelReconciler struct {
 	Log          logr.Logger
 	Scheme       *runtime.Scheme
 	SlackService slack.Service
+
+	// APIReader reads the token secrets of SlackWorkspaces without caching the secrets of the cluster
+	APIReader client.Reader
+
+	// Workspaces caches the slack Services of the SlackWorkspaces referenced by Channels
+	Workspaces *slack.ServiceCache
+
+	// SlackEvents receives Channels to reconcile because their slack channel changed, nil if slack events are disabled
+	SlackEvents <-chan event.GenericEvent
+
+	// TokenEvents receives Channels to reconcile because a valid API token was loaded
+	TokenEvents <-chan event.GenericEvent
 }
 
 // +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=slack.stakater.com,resources=channels/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=slackworkspaces,verbs=get;list;watch
 
 // Reconcile loop for the Channel resource
 func (r *ChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -66,16 +98,48 @@ func (r *ChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return reconcilerUtil.RequeueWithError(err)
 	}
 
-	// Channel is marked for deletion
+	// Channel is marked for deletion, which must not depend on its SlackWorkspace, e.g. when its namespace is deleted
 	if channel.GetDeletionTimestamp() != nil {
 		log.Info("Deletion timestamp found for channel " + req.Name)
 		if finalizerUtil.HasFinalizer(channel, channelFinalizer) {
-			return r.finalizeChannel(req, channel)
+			return r.finalizeChannel(ctx, channel)
 		}
 		// Finalizer doesn't exist so clean up is already done
 		return reconcilerUtil.DoNotRequeue()
 	}
 
+	slackService, err := r.SlackServiceFor(ctx, channel)
+	if err != nil {
+		log.Error(err, "Error getting slack workspace of channel")
+		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+	}
+
+	// Wait for a valid token instead of failing every slack API call
+	err = slackService.TokenError()
+	if err != nil {
+		log.Info("Waiting for a valid API token", "reason", err.Error())
+		meta.SetStatusCondition(&channel.Status.Conditions, metav1.Condition{
+			Type:               slackv1alpha1.TokenUnavailableConditionType,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: channel.Generation,
+			Reason:             "NoValidToken",
+			Message:            err.Error(),
+		})
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	if meta.FindStatusCondition(channel.Status.Conditions, slackv1alpha1.TokenUnavailableConditionType) != nil {
+		// Base object for patch, which patches using the merge-patch strategy with the given object as base.
+		channelPatchBase := client.MergeFrom(channel.DeepCopy())
+
+		meta.RemoveStatusCondition(&channel.Status.Conditions, slackv1alpha1.TokenUnavailableConditionType)
+
+		err = r.Status().Patch(ctx, channel, channelPatchBase)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, channel, err, true)
+		}
+	}
+
 	// Add finalizer if it doesn't exist
 	if !finalizerUtil.HasFinalizer(channel, channelFinalizer) {
 		log.Info("Adding finalizer for channel " + req.Name)
@@ -92,36 +156,39 @@ func (r *ChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	// Check for validity of slack channel custom resource
-	err = r.SlackService.IsValidChannel(channel)
+	err = slackService.IsValidChannel(channel)
 	if err != nil {
 		return reconcilerUtil.ManageError(r.Client, channel, err, true)
 	}
 
+	err = validateFeatures(channel)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+	}
+
+	members, err := r.getChannelMembers(ctx, channel, slackService)
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
 	if channel.Status.ID == "" {
+		if channel.Spec.Adopt != nil {
+			return r.adoptSlackChannel(ctx, channel, slackService, members)
+		}
+
 		name := channel.Spec.Name
 		isPrivate := channel.Spec.Private
 
 		log.Info("Creating new channel", "name", name)
 
-		channelID, err := r.SlackService.CreateChannel(name, isPrivate)
+		channelID, err := slackService.CreateChannel(name, isPrivate)
 		if err != nil {
 			if err.Error() == "name_taken" {
-				// Check if the channel already exists and then just reconstruct the status accordingly
-				existingChannel, err := r.SlackService.GetChannelByName(name)
-				if err != nil {
-					return reconcilerUtil.ManageError(r.Client, channel, err, false)
-				}
-
-				if existingChannel != nil && existingChannel.GroupConversation.IsArchived {
-					err = r.SlackService.UnArchiveChannel(existingChannel)
-					if err != nil {
-						return reconcilerUtil.ManageError(r.Client, channel, err, false)
-					}
-				}
-				channelID = &existingChannel.ID
-			} else {
+				// Existing channels are only managed through adoption, which checks that they can be taken over safely
+				err = fmt.Errorf("Slack channel %s already exists, set 'adopt' to manage it with this Channel", name)
 				return reconcilerUtil.ManageError(r.Client, channel, err, false)
 			}
+			return pkgutil.ManageError(ctx, r.Client, channel, err)
 		}
 
 		// Base object for patch, which patches using the merge-patch strategy with the given object as base.
@@ -134,79 +201,535 @@ func (r *ChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			log.Error(err, "Failed to update Channel status")
 			return reconcilerUtil.ManageError(r.Client, channel, err, true)
 		}
-		return r.updateSlackChannel(ctx, channel)
+		return r.syncSlackChannel(ctx, channel, slackService, members)
 	}
 
-	existingChannel, err := r.SlackService.GetChannel(channel.Status.ID)
+	existingChannel, err := slackService.GetChannel(channel.Status.ID)
 	if err != nil {
-		return reconcilerUtil.ManageError(r.Client, channel, err, true)
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
 	}
 
-	existingChannelCR := r.SlackService.GetChannelCRFromChannel(existingChannel)
+	existingChannelCR := slackService.GetChannelCRFromChannel(existingChannel)
 
 	err = slackv1alpha1.ValidateImmutableFields(existingChannelCR, channel)
 	if err != nil {
 		return reconcilerUtil.ManageError(r.Client, channel, err, true)
 	}
 
-	updated, err := r.SlackService.IsChannelUpdated(channel)
+	rolesChanged, err := r.applyChannelRoles(channel, slackService)
 	if err != nil {
 		return pkgutil.ManageError(ctx, r.Client, channel, err)
 	}
 
-	if !updated {
-		log.Info("Skipping update. No changes found")
-		return reconcilerUtil.DoNotRequeue()
+	sharingChanged, err := r.shareSlackChannel(ctx, channel, slackService)
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	bookmarksChanged, err := r.syncBookmarks(ctx, channel, slackService)
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	pinsChanged, err := r.syncPinnedMessages(ctx, channel, slackService)
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
 	}
 
-	return r.updateSlackChannel(ctx, channel)
+	welcomeMessageChanged, err := r.syncWelcomeMessage(ctx, channel, slackService)
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	drift, err := slackService.GetChannelDrift(channel, members, membershipPolicyOf(channel))
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	if drift == nil {
+		if channel.Status.Drift == nil && !rolesChanged && !sharingChanged && !bookmarksChanged && !pinsChanged && !welcomeMessageChanged &&
+			!membersChanged(channel, members) {
+			log.Info("Skipping update. No changes found")
+			return r.requeueForResync(channel)
+		}
+
+		if channel.Status.Drift != nil {
+			log.Info("Drift resolved on slack")
+		}
+		return r.updateSlackChannel(ctx, channel, slackService, members, nil)
+	}
+
+	// Changes made to the Channel spec are always applied, only changes made on slack are subject to the drift policy
+	specChanged := channel.Status.ObservedGeneration != channel.Generation
+
+	if driftPolicyOf(channel) == slackv1alpha1.ReportOnlyDriftPolicy && !specChanged {
+		log.Info("Reporting drift without updating channel", "fields", drift.Fields())
+		channel.Status.Drift = drift
+		meta.SetStatusCondition(&channel.Status.Conditions, metav1.Condition{
+			Type:               slackv1alpha1.DriftedConditionType,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: channel.Generation,
+			Reason:             "ChangedOnSlack",
+			Message:            fmt.Sprintf("Slack channel differs from the spec in %s", strings.Join(drift.Fields(), ", ")),
+		})
+		return r.manageSuccess(channel)
+	}
+
+	return r.updateSlackChannel(ctx, channel, slackService, members, drift)
 }
 
-func (r *ChannelReconciler) updateSlackChannel(ctx context.Context, channel *slackv1alpha1.Channel) (ctrl.Result, error) {
+// syncSlackChannel computes the drift of a newly created or adopted slack channel and updates it accordingly
+func (r *ChannelReconciler) syncSlackChannel(ctx context.Context, channel *slackv1alpha1.Channel, slackService slack.Service, members []string) (ctrl.Result, error) {
+	_, err := r.applyChannelRoles(channel, slackService)
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	_, err = r.shareSlackChannel(ctx, channel, slackService)
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	_, err = r.syncBookmarks(ctx, channel, slackService)
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	_, err = r.syncPinnedMessages(ctx, channel, slackService)
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	_, err = r.syncWelcomeMessage(ctx, channel, slackService)
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	drift, err := slackService.GetChannelDrift(channel, members, membershipPolicyOf(channel))
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	return r.updateSlackChannel(ctx, channel, slackService, members, drift)
+}
+
+// updateSlackChannel only calls the slack API for the fields that drifted, to save on rate limited requests
+func (r *ChannelReconciler) updateSlackChannel(ctx context.Context, channel *slackv1alpha1.Channel, slackService slack.Service, members []string, drift *slackv1alpha1.ChannelDrift) (ctrl.Result, error) {
+	channelID := channel.Status.ID
+	log := r.Log.WithValues("channelID", channelID)
+
+	if drift == nil {
+		drift = &slackv1alpha1.ChannelDrift{}
+	} else {
+		log.Info("Updating channel details", "fields", drift.Fields())
+	}
+
+	if drift.Name != nil {
+		_, err := slackService.RenameChannel(channelID, channel.Spec.Name)
+		if err != nil {
+			log.Error(err, "Error renaming channel")
+			return pkgutil.ManageError(ctx, r.Client, channel, err)
+		}
+	}
+
+	if drift.Topic != nil {
+		_, err := slackService.SetTopic(channelID, channel.Spec.Topic)
+		if err != nil {
+			log.Error(err, "Error setting channel topic")
+			return pkgutil.ManageError(ctx, r.Client, channel, err)
+		}
+	}
+
+	if drift.Description != nil {
+		_, err := slackService.SetDescription(channelID, channel.Spec.Description)
+		if err != nil {
+			log.Error(err, "Error setting channel description")
+			return pkgutil.ManageError(ctx, r.Client, channel, err)
+		}
+	}
+
+	// Members that can't join are reported in the status, so that the others still get invited
+	if membershipPolicyOf(channel) == slackv1alpha1.IgnoreMembershipPolicy {
+		channel.Status.Members = nil
+	} else {
+		memberStatuses, err := slackService.InviteUsers(channelID, members)
+		if err != nil {
+			log.Error(err, "Error inviting users to channel")
+			return pkgutil.ManageError(ctx, r.Client, channel, err)
+		}
+		channel.Status.Members = memberStatuses
+	}
+	setMembersDegradedCondition(channel)
+
+	if len(drift.ExtraUsers) > 0 {
+		err := slackService.RemoveUsers(channelID, members)
+		if err != nil {
+			log.Error(err, "Error removing users from the channel")
+			return pkgutil.ManageError(ctx, r.Client, channel, err)
+		}
+	}
+
+	channel.Status.ObservedGeneration = channel.Generation
+	channel.Status.Drift = nil
+	meta.RemoveStatusCondition(&channel.Status.Conditions, slackv1alpha1.DriftedConditionType)
+
+	return r.manageSuccess(channel)
+}
+
+// membersChanged returns true if the membership status of the Channel doesn't list the given members
+func membersChanged(channel *slackv1alpha1.Channel, members []string) bool {
+	if membershipPolicyOf(channel) == slackv1alpha1.IgnoreMembershipPolicy {
+		return channel.Status.Members != nil
+	}
+
+	var emails []string
+	for _, member := range channel.Status.Members {
+		emails = append(emails, member.Email)
+	}
+	return !pkgutil.SameElements(emails, members)
+}
+
+// setMembersDegradedCondition sets the MembersDegraded condition while some members are not in the slack channel
+func setMembersDegradedCondition(channel *slackv1alpha1.Channel) {
+	var degraded []string
+	for _, member := range channel.Status.Members {
+		if member.State != slackv1alpha1.JoinedMemberState {
+			degraded = append(degraded, fmt.Sprintf("%s (%s)", member.Email, member.State))
+		}
+	}
+
+	if len(degraded) == 0 {
+		meta.RemoveStatusCondition(&channel.Status.Conditions, slackv1alpha1.MembersDegradedConditionType)
+		return
+	}
+
+	meta.SetStatusCondition(&channel.Status.Conditions, metav1.Condition{
+		Type:               slackv1alpha1.MembersDegradedConditionType,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: channel.Generation,
+		Reason:             "MembersNotJoined",
+		Message:            fmt.Sprintf("Members not in the slack channel: %s", strings.Join(degraded, ", ")),
+	})
+}
+
+// applyChannelRoles assigns the channel managers and the posting policy and records them in the status, returning
+// whether the status changed. Only changes to the spec are applied, slack has no cheap way to read them back.
+func (r *ChannelReconciler) applyChannelRoles(channel *slackv1alpha1.Channel, slackService slack.Service) (bool, error) {
 	channelID := channel.Status.ID
 	log := r.Log.WithValues("channelID", channelID)
 
-	log.Info("Updating channel details")
+	addedManagers := pkgutil.Difference(channel.Spec.Managers, channel.Status.Managers)
+	removedManagers := pkgutil.Difference(channel.Status.Managers, channel.Spec.Managers)
 
-	name := channel.Spec.Name
-	users := channel.Spec.Users
-	topic := channel.Spec.Topic
-	description := channel.Spec.Description
+	if len(addedManagers) > 0 {
+		log.Info("Adding channel managers", "managers", addedManagers)
+		err := slackService.AddChannelManagers(channelID, addedManagers)
+		if err != nil {
+			return false, err
+		}
+	}
 
-	_, err := r.SlackService.RenameChannel(channelID, name)
+	if len(removedManagers) > 0 {
+		log.Info("Removing channel managers", "managers", removedManagers)
+		err := slackService.RemoveChannelManagers(channelID, removedManagers)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	managersChanged := len(addedManagers) > 0 || len(removedManagers) > 0
+	channel.Status.Managers = channel.Spec.Managers
+
+	postingPolicy := channel.Spec.PostingPolicy
+	postingPolicyChanged := postingPolicy != channel.Status.PostingPolicy ||
+		(postingPolicy == slackv1alpha1.ManagersPostingPolicy && managersChanged)
+
+	if postingPolicyChanged {
+		// Removing the posting policy from the spec lets members post again
+		if postingPolicy == "" {
+			postingPolicy = slackv1alpha1.MembersPostingPolicy
+		}
+
+		log.Info("Setting posting policy", "postingPolicy", postingPolicy)
+		err := slackService.SetPostingPolicy(channelID, postingPolicy, channel.Spec.Managers)
+		if err != nil {
+			return managersChanged, err
+		}
+		channel.Status.PostingPolicy = channel.Spec.PostingPolicy
+	}
+
+	return managersChanged || postingPolicyChanged, nil
+}
+
+// shareSlackChannel sends the Slack Connect invitations that were not sent yet and tracks whether they were accepted,
+// returning whether the status changed. Each invitation is recorded right after it was sent, so that it is not sent
+// again if a later one fails.
+func (r *ChannelReconciler) shareSlackChannel(ctx context.Context, channel *slackv1alpha1.Channel, slackService slack.Service) (bool, error) {
+	channelID := channel.Status.ID
+	log := r.Log.WithValues("channelID", channelID)
+	changed := false
+
+	for _, invitation := range channel.Spec.SharedWith {
+		// An invitation is sent again when the organization expected to accept it changed
+		status := findSharedInvitation(channel.Status.SharedInvitations, invitation.Email)
+		if status != nil && status.TeamID == invitation.TeamID {
+			continue
+		}
+
+		inviteID, err := slackService.InviteShared(channelID, invitation.Email, invitation.ExternalLimited)
+		if err != nil {
+			return changed, err
+		}
+
+		log.Info("Sent Slack Connect invitation", "email", invitation.Email, "teamID", invitation.TeamID, "inviteID", inviteID)
+		sent := slackv1alpha1.SharedInvitationStatus{
+			Email:    invitation.Email,
+			TeamID:   invitation.TeamID,
+			InviteID: inviteID,
+			State:    slackv1alpha1.PendingSharedInvitationState,
+		}
+		err = r.recordStatus(ctx, channel, func() {
+			if status := findSharedInvitation(channel.Status.SharedInvitations, invitation.Email); status != nil {
+				*status = sent
+			} else {
+				channel.Status.SharedInvitations = append(channel.Status.SharedInvitations, sent)
+			}
+		})
+		if err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+
+	// Invitations removed from the spec are forgotten, slack has no method to revoke them
+	var invitations []slackv1alpha1.SharedInvitationStatus
+	pending := false
+	for _, status := range channel.Status.SharedInvitations {
+		for _, invitation := range channel.Spec.SharedWith {
+			if invitation.Email == status.Email {
+				invitations = append(invitations, status)
+				pending = pending || status.State == slackv1alpha1.PendingSharedInvitationState
+				break
+			}
+		}
+	}
+	if len(invitations) != len(channel.Status.SharedInvitations) {
+		changed = true
+	}
+	channel.Status.SharedInvitations = invitations
+
+	if !pending {
+		return changed, nil
+	}
+
+	externalMembers, err := slackService.GetExternalMembers(channelID)
 	if err != nil {
-		log.Error(err, "Error renaming channel")
-		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+		return changed, err
+	}
+
+	for i := range channel.Status.SharedInvitations {
+		status := &channel.Status.SharedInvitations[i]
+		if status.State == slackv1alpha1.PendingSharedInvitationState && invitationAccepted(status, externalMembers) {
+			log.Info("Slack Connect invitation was accepted", "email", status.Email, "inviteID", status.InviteID)
+			status.State = slackv1alpha1.AcceptedSharedInvitationState
+			changed = true
+		}
+	}
+
+	return changed, nil
+}
+
+// findSharedInvitation returns the status of the invitation sent to the email, or nil if none was sent
+func findSharedInvitation(invitations []slackv1alpha1.SharedInvitationStatus, email string) *slackv1alpha1.SharedInvitationStatus {
+	for i := range invitations {
+		if invitations[i].Email == email {
+			return &invitations[i]
+		}
 	}
+	return nil
+}
+
+// invitationAccepted returns true if a member of the invited organization, or the recipient if the organization is
+// not known, joined the channel
+func invitationAccepted(invitation *slackv1alpha1.SharedInvitationStatus, externalMembers []slackapi.User) bool {
+	for _, member := range externalMembers {
+		if invitation.TeamID != "" && member.TeamID == invitation.TeamID {
+			return true
+		}
+		if invitation.TeamID == "" && strings.EqualFold(member.Profile.Email, invitation.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordStatus applies record to the status and patches it right away, so that a slack side effect is not repeated
+// when the reconcile fails later on. The changes of the status that were not persisted yet are kept in memory.
+func (r *ChannelReconciler) recordStatus(ctx context.Context, channel *slackv1alpha1.Channel, record func()) error {
+	// Base object for patch, which patches using the merge-patch strategy with the given object as base.
+	channelPatchBase := client.MergeFrom(channel.DeepCopy())
+
+	record()
 
-	_, err = r.SlackService.SetTopic(channelID, topic)
+	// The response is read into a copy, it would revert the other changes of the status otherwise
+	patched := channel.DeepCopy()
+	err := r.Status().Patch(ctx, patched, channelPatchBase)
 	if err != nil {
-		log.Error(err, "Error setting channel topic")
-		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+		return err
 	}
+	channel.ResourceVersion = patched.ResourceVersion
+	return nil
+}
 
-	_, err = r.SlackService.SetDescription(channelID, description)
+func (r *ChannelReconciler) manageSuccess(channel *slackv1alpha1.Channel) (ctrl.Result, error) {
+	result, err := reconcilerUtil.ManageSuccess(r.Client, channel)
 	if err != nil {
-		log.Error(err, "Error setting channel description")
-		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+		return result, err
+	}
+	return r.requeueForResync(channel)
+}
+
+// SlackServiceFor returns the slack Service of the workspace the Channel belongs to
+func (r *ChannelReconciler) SlackServiceFor(ctx context.Context, channel *slackv1alpha1.Channel) (slack.Service, error) {
+	return getChannelService(ctx, r.Client, r.APIReader, r.Workspaces, r.SlackService, channel)
+}
+
+// getChannelService returns the slack Service of the SlackWorkspace of the Channel, or the default one if it has none
+func getChannelService(ctx context.Context, k8sReader client.Reader, apiReader client.Reader, workspaces *slack.ServiceCache,
+	defaultService slack.Service, channel *slackv1alpha1.Channel) (slack.Service, error) {
+	if channel.Spec.Workspace == "" {
+		return defaultService, nil
+	}
+
+	workspace := &slackv1alpha1.SlackWorkspace{}
+	err := k8sReader.Get(ctx, types.NamespacedName{Namespace: channel.Namespace, Name: channel.Spec.Workspace}, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return getWorkspaceService(apiReader, workspaces, workspace)
+}
+
+func (r *ChannelReconciler) requeueForResync(channel *slackv1alpha1.Channel) (ctrl.Result, error) {
+	resyncPeriod := config.Get().ResyncPeriod.Duration
+	if channel.Spec.ResyncPeriod != nil {
+		resyncPeriod = channel.Spec.ResyncPeriod.Duration
+	}
+
+	if resyncPeriod <= 0 {
+		return reconcilerUtil.DoNotRequeue()
+	}
+	return reconcilerUtil.RequeueAfter(wait.Jitter(resyncPeriod, resyncJitterFactor))
+}
+
+// validateFeatures rejects Channels that use features disabled in the operator config
+func validateFeatures(channel *slackv1alpha1.Channel) error {
+	features := config.Get().Features
+
+	if channel.Spec.Adopt != nil && channel.Status.ID == "" && !features.Adoption {
+		return fmt.Errorf("Adopting existing slack channels is disabled in the operator config")
+	}
+	if channel.Spec.Workspace != "" && !features.SlackWorkspaces {
+		return fmt.Errorf("SlackWorkspaces are disabled in the operator config")
+	}
+	return nil
+}
+
+// driftPolicyOf returns the drift policy of the Channel, or the operator default if it has none
+func driftPolicyOf(channel *slackv1alpha1.Channel) slackv1alpha1.DriftPolicy {
+	if channel.Spec.DriftPolicy != "" {
+		return channel.Spec.DriftPolicy
+	}
+	return slackv1alpha1.DriftPolicy(config.Get().ChannelDefaults.DriftPolicy)
+}
+
+// membershipPolicyOf returns the membership policy of the Channel, or the operator default if it has none
+func membershipPolicyOf(channel *slackv1alpha1.Channel) slackv1alpha1.MembershipPolicy {
+	if channel.Spec.MembershipPolicy != "" {
+		return channel.Spec.MembershipPolicy
+	}
+	return slackv1alpha1.MembershipPolicy(config.Get().ChannelDefaults.MembershipPolicy)
+}
+
+// deletionPolicyOf returns the deletion policy and archive suffix of the Channel, or the operator defaults for the
+// ones it has none, which only happens if it was created while the webhooks were disabled
+func deletionPolicyOf(channel *slackv1alpha1.Channel) (slackv1alpha1.DeletionPolicy, string) {
+	defaults := config.Get().ChannelDefaults
+
+	deletionPolicy := channel.Spec.DeletionPolicy
+	if deletionPolicy == "" {
+		deletionPolicy = slackv1alpha1.DeletionPolicy(defaults.DeletionPolicy)
 	}
 
-	errorlist := r.SlackService.InviteUsers(channelID, users)
-	if len(errorlist) > 0 {
-		log.Error(err, "Error inviting users to channel")
-		return pkgutil.ManageError(ctx, r.Client, channel, pkgutil.MapErrorListToError(errorlist))
+	archiveSuffix := channel.Spec.ArchiveSuffix
+	if archiveSuffix == "" {
+		archiveSuffix = defaults.ArchiveSuffix
+	}
+	return deletionPolicy, archiveSuffix
+}
+
+func (r *ChannelReconciler) adoptSlackChannel(ctx context.Context, channel *slackv1alpha1.Channel, slackService slack.Service, members []string) (ctrl.Result, error) {
+	adopt := channel.Spec.Adopt
+	log := r.Log.WithValues("adoptID", adopt.ID, "adoptName", adopt.Name)
+
+	log.Info("Adopting existing channel")
+
+	getChannel, key := slackService.GetChannel, adopt.ID
+	if adopt.ID == "" {
+		getChannel, key = slackService.GetChannelByName, adopt.Name
 	}
 
-	err = r.SlackService.RemoveUsers(channelID, users)
+	existingChannel, err := getChannel(key)
 	if err != nil {
-		log.Error(err, "Error removing users from the channel")
+		log.Error(err, "Error fetching channel to adopt")
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	if existingChannel.IsPrivate != channel.Spec.Private {
+		err = fmt.Errorf("Channel %s can not be adopted because its privacy does not match 'private: %t'", existingChannel.ID, channel.Spec.Private)
 		return reconcilerUtil.ManageError(r.Client, channel, err, false)
 	}
 
-	return reconcilerUtil.ManageSuccess(r.Client, channel)
+	if !adopt.AllowDestructiveChanges && membershipPolicyOf(channel) == slackv1alpha1.AuthoritativeMembershipPolicy {
+		extraUsers, err := slackService.GetExtraUsers(existingChannel.ID, members)
+		if err != nil {
+			return pkgutil.ManageError(ctx, r.Client, channel, err)
+		}
+
+		if len(extraUsers) > 0 {
+			var extraUserEmails []string
+			for _, user := range extraUsers {
+				extraUserEmails = append(extraUserEmails, user.Profile.Email)
+			}
+			err = fmt.Errorf("Channel %s can not be adopted because members %s would be removed, set 'allowDestructiveChanges' to adopt it anyway",
+				existingChannel.ID, strings.Join(extraUserEmails, ", "))
+			return reconcilerUtil.ManageError(r.Client, channel, err, false)
+		}
+	}
+
+	if existingChannel.IsArchived {
+		err = slackService.UnArchiveChannel(existingChannel)
+		if err != nil {
+			return pkgutil.ManageError(ctx, r.Client, channel, err)
+		}
+	}
+
+	// Base object for patch, which patches using the merge-patch strategy with the given object as base.
+	channelPatchBase := client.MergeFrom(channel.DeepCopy())
+
+	channel.Status.ID = existingChannel.ID
+	channel.Status.Adopted = true
+
+	err = r.Status().Patch(ctx, channel, channelPatchBase)
+	if err != nil {
+		log.Error(err, "Failed to update Channel status")
+		return reconcilerUtil.ManageError(r.Client, channel, err, true)
+	}
+
+	return r.syncSlackChannel(ctx, channel, slackService, members)
 }
 
-func (r *ChannelReconciler) finalizeChannel(req ctrl.Request, channel *slackv1alpha1.Channel) (ctrl.Result, error) {
+func (r *ChannelReconciler) finalizeChannel(ctx context.Context, channel *slackv1alpha1.Channel) (ctrl.Result, error) {
 	if channel == nil {
 		return reconcilerUtil.DoNotRequeue()
 	}
@@ -214,21 +737,64 @@ func (r *ChannelReconciler) finalizeChannel(req ctrl.Request, channel *slackv1al
 	channelID := channel.Status.ID
 	log := r.Log.WithValues("channelID", channelID)
 
-	err := r.SlackService.ArchiveChannel(channelID)
+	slackService, err := r.SlackServiceFor(ctx, channel)
+	if errors.IsNotFound(err) {
+		// The SlackWorkspace or its token secret was deleted first, e.g. with the namespace, so the slack channel can't
+		// be reached anymore and is left as it is
+		log.Info("Slack workspace of channel no longer exists, retaining channel", "reason", err.Error())
+		return r.removeFinalizer(ctx, channel)
+	}
+	if err != nil {
+		log.Error(err, "Error getting slack workspace of channel")
+		return reconcilerUtil.ManageError(r.Client, channel, err, true)
+	}
 
-	if err != nil && err.Error() != "channel_not_found" && err.Error() != "already_archived" {
-		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+	// The channel is cleaned up once a valid token was loaded
+	err = slackService.TokenError()
+	if err != nil {
+		log.Info("Waiting for a valid API token to finalize channel", "reason", err.Error())
+		return pkgutil.ManageError(ctx, r.Client, channel, err)
+	}
+
+	deletionPolicy, archiveSuffix := deletionPolicyOf(channel)
+
+	switch deletionPolicy {
+	case slackv1alpha1.RetainDeletionPolicy:
+		log.Info("Retaining channel as per deletion policy")
+	case slackv1alpha1.RenameThenArchiveDeletionPolicy:
+		newName := channel.Spec.Name + archiveSuffix
+		_, err := slackService.RenameChannel(channelID, newName)
+
+		// Channels created without the webhook may have a name that is too long for the suffix, retrying the rename
+		// would block the deletion forever, so they are archived under their name
+		if err != nil && err.Error() == "invalid_name_maxlength" {
+			log.Info("Archiving channel without renaming it, the name with the archive suffix is too long", "name", newName)
+		} else if err != nil && err.Error() != "channel_not_found" && err.Error() != "is_archived" {
+			return pkgutil.ManageError(ctx, r.Client, channel, err)
+		}
+		fallthrough
+	default:
+		err := slackService.ArchiveChannel(channelID)
+
+		if err != nil && err.Error() != "channel_not_found" && err.Error() != "already_archived" {
+			return pkgutil.ManageError(ctx, r.Client, channel, err)
+		}
 	}
 
+	return r.removeFinalizer(ctx, channel)
+}
+
+// removeFinalizer lets the Channel be deleted once its slack channel was cleaned up
+func (r *ChannelReconciler) removeFinalizer(ctx context.Context, channel *slackv1alpha1.Channel) (ctrl.Result, error) {
 	// Base object for patch, which patches using the merge-patch strategy with the given object as base.
 	channelPatchBase := client.MergeFrom(channel.DeepCopy())
 
 	finalizerUtil.DeleteFinalizer(channel, channelFinalizer)
-	log.V(1).Info("Finalizer removed for channel")
+	r.Log.V(1).Info("Finalizer removed for channel", "channelID", channel.Status.ID)
 
-	err = r.Client.Patch(context.Background(), channel, channelPatchBase)
+	err := r.Client.Patch(ctx, channel, channelPatchBase)
 	if err != nil {
-		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+		return reconcilerUtil.ManageError(r.Client, channel, err, true)
 	}
 
 	return reconcilerUtil.DoNotRequeue()
@@ -236,7 +802,52 @@ func (r *ChannelReconciler) finalizeChannel(req ctrl.Request, channel *slackv1al
 
 // SetupWithManager - Controller-Manager binding configuration
 func (r *ChannelReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &slackv1alpha1.Channel{}, channelWorkspaceField, func(obj client.Object) []string {
+		channel := obj.(*slackv1alpha1.Channel)
+		if channel.Spec.Workspace == "" {
+			return nil
+		}
+		return []string{channel.Spec.Workspace}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &slackv1alpha1.Channel{}, channelMembersFromField, indexMembersFrom)
+	if err != nil {
+		return err
+	}
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&slackv1alpha1.Channel{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &slackv1alpha1.SlackWorkspace{}}, handler.EnqueueRequestsFromMapFunc(r.channelsOfWorkspace)).
+		Watches(&source.Kind{Type: &rbacv1.RoleBinding{}}, handler.EnqueueRequestsFromMapFunc(r.channelsOfRoleBinding)).
+		Watches(&source.Kind{Type: &rbacv1.ClusterRoleBinding{}}, handler.EnqueueRequestsFromMapFunc(r.channelsOfClusterRoleBinding))
+
+	if r.SlackEvents != nil {
+		controllerBuilder = controllerBuilder.Watches(&source.Channel{Source: r.SlackEvents}, &handler.EnqueueRequestForObject{})
+	}
+
+	if r.TokenEvents != nil {
+		controllerBuilder = controllerBuilder.Watches(&source.Channel{Source: r.TokenEvents}, &handler.EnqueueRequestForObject{})
+	}
+
+	return controllerBuilder.Complete(r)
+}
+
+// channelsOfWorkspace returns requests for the Channels which belong to the SlackWorkspace
+func (r *ChannelReconciler) channelsOfWorkspace(workspace client.Object) []reconcile.Request {
+	channelList := &slackv1alpha1.ChannelList{}
+	err := r.List(context.Background(), channelList, client.InNamespace(workspace.GetNamespace()),
+		client.MatchingFields{channelWorkspaceField: workspace.GetName()})
+	if err != nil {
+		r.Log.Error(err, "Error listing Channels of SlackWorkspace", "workspace", workspace.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, channel := range channelList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: channel.Namespace, Name: channel.Name}})
+	}
+	return requests
 }
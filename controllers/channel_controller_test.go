@@ -3,12 +3,17 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/slack"
 	"github.com/stakater/slack-operator/pkg/slack/mock"
 	slackMock "github.com/stakater/slack-operator/pkg/slack/mock"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -84,14 +89,440 @@ var _ = Describe("ChannelController", func() {
 				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
 			})
 
-			It("should set error condition when user does not exists", func() {
-				emailList := []string{"nonexistent@slack.com"}
+			It("should set members degraded condition when user does not exists", func() {
+				emailList := []string{mock.ExistingUserEmail, "nonexistent@slack.com"}
 				_ = util.CreateChannel(channelName, true, "", "", emailList, ns)
 				channel := util.GetChannel(channelName, ns)
 
+				Expect(len(channel.Status.Conditions)).To(Equal(2))
+				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+
+				degraded := meta.FindStatusCondition(channel.Status.Conditions, slackv1alpha1.MembersDegradedConditionType)
+				Expect(degraded).ToNot(BeNil())
+				Expect(degraded.Message).To(ContainSubstring(emailList[1]))
+
+				Expect(channel.Status.Members).To(HaveLen(2))
+				Expect(channel.Status.Members[0].State).To(Equal(slackv1alpha1.JoinedMemberState))
+				Expect(channel.Status.Members[1].State).To(Equal(slackv1alpha1.NotFoundMemberState))
+				Expect(channel.Status.Members[1].LastError).To(Equal(fmt.Sprintf("Error fetching user by Email %s", emailList[1])))
+			})
+		})
+
+		Context("With user groups", func() {
+			It("should set success condition when user group exists", func() {
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", nil, ns)
+				channelObject.Spec.UserGroups = []string{"@" + mock.UserGroupHandle}
+				_ = util.SubmitChannel(channelObject)
+				channel := util.GetChannel(channelName, ns)
+
+				Expect(len(channel.Status.Conditions)).To(Equal(1))
+				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+			})
+
+			It("should set error condition when user group does not exist", func() {
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.UserGroups = []string{"ectoplasm"}
+				_ = util.SubmitChannel(channelObject)
+				channel := util.GetChannel(channelName, ns)
+
 				Expect(len(channel.Status.Conditions)).To(Equal(1))
 				Expect(channel.Status.Conditions[0].Reason).To(Equal("Failed"))
-				Expect(channel.Status.Conditions[0].Message).To(Equal(fmt.Sprintf("Error fetching user by Email %s", emailList[0])))
+				Expect(channel.Status.Conditions[0].Message).To(Equal(fmt.Sprintf(slack.UserGroupNotFoundError, "ectoplasm")))
+			})
+		})
+	})
+
+	Describe("Creating SlackChannel resource whose name is taken", func() {
+		It("should set error condition instead of taking over the existing channel", func() {
+			channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+			channelObject.Spec.Name = mock.NameTakenConversationName
+			_ = util.SubmitChannel(channelObject)
+			channel := util.GetChannel(channelName, ns)
+
+			Expect(channel.Status.ID).To(BeEmpty())
+			Expect(channel.Status.Adopted).To(BeFalse())
+			Expect(channel.Status.Conditions[0].Reason).To(Equal("Failed"))
+			Expect(channel.Status.Conditions[0].Message).To(ContainSubstring("set 'adopt'"))
+		})
+	})
+
+	Describe("Creating SlackChannel resource with managers", func() {
+		It("should report the applied managers and posting policy", func() {
+			channelObject := util.CreateSlackChannelObject(channelName, false, "", "", nil, ns)
+			channelObject.Spec.Managers = []string{mock.ExistingUserEmail}
+			channelObject.Spec.PostingPolicy = slackv1alpha1.ManagersPostingPolicy
+			_ = util.SubmitChannel(channelObject)
+			channel := util.GetChannel(channelName, ns)
+
+			Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+			Expect(channel.Status.Managers).To(Equal([]string{mock.ExistingUserEmail}))
+			Expect(channel.Status.PostingPolicy).To(Equal(slackv1alpha1.ManagersPostingPolicy))
+		})
+	})
+
+	Describe("Creating SlackChannel resource shared with external organizations", func() {
+		It("should track the accepted invitations", func() {
+			channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+			channelObject.Spec.SharedWith = []slackv1alpha1.SharedInvitation{
+				{Email: mock.ExternalUserEmail, TeamID: mock.ExternalTeamID},
+				{Email: "consultant@example.com"},
+			}
+			_ = util.SubmitChannel(channelObject)
+			channel := util.GetChannel(channelName, ns)
+
+			Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+			Expect(channel.Status.SharedInvitations).To(Equal([]slackv1alpha1.SharedInvitationStatus{
+				{Email: mock.ExternalUserEmail, TeamID: mock.ExternalTeamID, InviteID: mock.InviteID, State: slackv1alpha1.AcceptedSharedInvitationState},
+				{Email: "consultant@example.com", InviteID: mock.InviteID, State: slackv1alpha1.PendingSharedInvitationState},
+			}))
+		})
+
+		It("should invite the new organization when the team ID of an invitation changes", func() {
+			channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+			channelObject.Spec.SharedWith = []slackv1alpha1.SharedInvitation{{Email: "consultant@example.com"}}
+			_ = util.SubmitChannel(channelObject)
+
+			channel := util.GetChannel(channelName, ns)
+			channel.Spec.SharedWith[0].TeamID = mock.ExternalTeamID
+			err := k8sClient.Update(ctx, channel)
+			if err != nil {
+				Fail(err.Error())
+			}
+
+			mock.ResetCalls()
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: channelName, Namespace: ns}}
+			_, err = r.Reconcile(context.Background(), req)
+			if err != nil {
+				Fail(err.Error())
+			}
+
+			invitations := mock.Calls("conversations.inviteShared")
+			Expect(invitations).To(HaveLen(1))
+			Expect(invitations[0].Params.Get("emails")).To(Equal("consultant@example.com"))
+
+			channel = util.GetChannel(channelName, ns)
+			Expect(channel.Status.SharedInvitations).To(HaveLen(1))
+			Expect(channel.Status.SharedInvitations[0].TeamID).To(Equal(mock.ExternalTeamID))
+			Expect(channel.Status.SharedInvitations[0].InviteID).To(Equal(mock.InviteID))
+		})
+	})
+
+	Describe("Creating SlackChannel resource with bookmarks and pinned messages", func() {
+		It("should track the bookmarks and messages it created", func() {
+			channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+			channelObject.Spec.Bookmarks = []slackv1alpha1.Bookmark{{Title: "Dashboard", Link: "https://grafana.example.com", Emoji: ":chart:"}}
+			channelObject.Spec.PinnedMessages = []slackv1alpha1.PinnedMessage{{Text: "Read the runbook before paging"}}
+			_ = util.SubmitChannel(channelObject)
+			channel := util.GetChannel(channelName, ns)
+
+			Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+			Expect(channel.Status.Bookmarks).To(Equal([]slackv1alpha1.BookmarkStatus{{ID: mock.NewBookmarkID, Title: "Dashboard"}}))
+			Expect(channel.Status.PinnedMessages).To(Equal([]slackv1alpha1.PinnedMessageStatus{
+				{Timestamp: mock.NewMessageTimestamp, Text: "Read the runbook before paging"},
+			}))
+		})
+
+		It("should unpin and delete the messages removed from the spec", func() {
+			channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+			channelObject.Spec.PinnedMessages = []slackv1alpha1.PinnedMessage{{Text: "Read the runbook before paging"}}
+			_ = util.SubmitChannel(channelObject)
+
+			channel := util.GetChannel(channelName, ns)
+			channel.Spec.PinnedMessages = nil
+			err := k8sClient.Update(ctx, channel)
+			if err != nil {
+				Fail(err.Error())
+			}
+
+			mock.ResetCalls()
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: channelName, Namespace: ns}}
+			_, err = r.Reconcile(context.Background(), req)
+			if err != nil {
+				Fail(err.Error())
+			}
+
+			channel = util.GetChannel(channelName, ns)
+			Expect(channel.Status.PinnedMessages).To(BeEmpty())
+
+			methods := mock.Methods()
+			Expect(mock.Calls("pins.remove")).To(HaveLen(1))
+			Expect(mock.Calls("chat.delete")).To(HaveLen(1))
+			Expect(indexOf(methods, "pins.remove")).To(BeNumerically("<", indexOf(methods, "chat.delete")))
+		})
+	})
+
+	Describe("Creating SlackChannel resource with a welcome message", func() {
+		It("should post the welcome message once and edit it when the template changes", func() {
+			channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+			channelObject.Spec.WelcomeMessage = &slackv1alpha1.WelcomeMessage{Text: "Welcome to {{ .Spec.Name }} in {{ .Namespace }}"}
+
+			mock.ResetCalls()
+			_ = util.SubmitChannel(channelObject)
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: channelName, Namespace: ns}}
+			_, err := r.Reconcile(context.Background(), req)
+			if err != nil {
+				Fail(err.Error())
+			}
+
+			channel := util.GetChannel(channelName, ns)
+			Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+			Expect(channel.Status.WelcomeMessage).ToNot(BeNil())
+			Expect(channel.Status.WelcomeMessage.Timestamp).To(Equal(mock.NewMessageTimestamp))
+			checksum := channel.Status.WelcomeMessage.Checksum
+
+			channel.Spec.WelcomeMessage.Text = "Welcome to #{{ .Spec.Name }}"
+			err = k8sClient.Update(ctx, channel)
+			if err != nil {
+				Fail(err.Error())
+			}
+
+			_, err = r.Reconcile(context.Background(), req)
+			if err != nil {
+				Fail(err.Error())
+			}
+
+			channel = util.GetChannel(channelName, ns)
+			Expect(channel.Status.WelcomeMessage.Timestamp).To(Equal(mock.NewMessageTimestamp))
+			Expect(channel.Status.WelcomeMessage.Checksum).ToNot(Equal(checksum))
+
+			Expect(mock.Calls("chat.postMessage")).To(HaveLen(1))
+			updates := mock.Calls("chat.update")
+			Expect(updates).To(HaveLen(1))
+			Expect(updates[0].Params.Get("ts")).To(Equal(mock.NewMessageTimestamp))
+		})
+
+		It("should not post the welcome message into an adopted channel", func() {
+			channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+			channelObject.Spec.WelcomeMessage = &slackv1alpha1.WelcomeMessage{Text: "Welcome to {{ .Spec.Name }}"}
+			channelObject.Spec.Adopt = &slackv1alpha1.ChannelAdoption{
+				ID:                      slackMock.PublicConversationID,
+				AllowDestructiveChanges: true,
+			}
+
+			mock.ResetCalls()
+			_ = util.SubmitChannel(channelObject)
+			channel := util.GetChannel(channelName, ns)
+
+			Expect(channel.Status.Adopted).To(BeTrue())
+			Expect(channel.Status.WelcomeMessage).To(BeNil())
+			Expect(mock.Calls("chat.postMessage")).To(BeEmpty())
+		})
+	})
+
+	Describe("Creating SlackChannel resource with members from role bindings", func() {
+		var roleBinding *rbacv1.RoleBinding
+
+		BeforeEach(func() {
+			roleBinding = &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        util.RandSeq(10),
+					Namespace:   ns,
+					Annotations: map[string]string{slackv1alpha1.UserEmailsAnnotation: `{"spengler": "spengler@ghostbusters.example.com"}`},
+				},
+				RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "edit"},
+				Subjects: []rbacv1.Subject{
+					{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: mock.ExistingUserEmail},
+					{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "spengler"},
+					{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "ghostbusters"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, roleBinding)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			_ = k8sClient.Delete(ctx, roleBinding)
+		})
+
+		It("should invite the users of the selected role bindings", func() {
+			channelObject := util.CreateSlackChannelObject(channelName, false, "", "", nil, ns)
+			channelObject.Spec.MembersFrom = &slackv1alpha1.MembersFrom{
+				RoleBindings: &slackv1alpha1.BindingSelector{Roles: []string{"edit"}},
+			}
+			_ = util.SubmitChannel(channelObject)
+			channel := util.GetChannel(channelName, ns)
+
+			Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+			Expect(channel.Status.Members).To(Equal([]slackv1alpha1.MemberStatus{
+				{Email: mock.ExistingUserEmail, UserID: "W012A3CDE", State: slackv1alpha1.JoinedMemberState},
+				{Email: "spengler@ghostbusters.example.com", State: slackv1alpha1.NotFoundMemberState,
+					LastError: "Error fetching user by Email spengler@ghostbusters.example.com"},
+			}))
+		})
+
+		It("should ignore the role bindings which are not selected", func() {
+			channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+			channelObject.Spec.MembersFrom = &slackv1alpha1.MembersFrom{
+				RoleBindings: &slackv1alpha1.BindingSelector{Roles: []string{"admin"}},
+			}
+			_ = util.SubmitChannel(channelObject)
+			channel := util.GetChannel(channelName, ns)
+
+			Expect(len(channel.Status.Conditions)).To(Equal(1))
+			Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+		})
+	})
+
+	Describe("Creating SlackChannel resource in a SlackWorkspace", func() {
+		var workspaceName string
+
+		BeforeEach(func() {
+			workspaceName = util.RandSeq(10)
+		})
+
+		AfterEach(func() {
+			util.TryDeleteSlackWorkspace(workspaceName, ns)
+		})
+
+		Context("With an existing workspace", func() {
+			It("should create the channel with the token of the workspace", func() {
+				_ = util.CreateSlackWorkspace(workspaceName, "xoxb-token", ns)
+
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.Workspace = workspaceName
+				_ = util.SubmitChannel(channelObject)
+				channel := util.GetChannel(channelName, ns)
+
+				Expect(channel.Status.ID).To(Equal(slackMock.PublicConversationID))
+				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+			})
+
+			It("should remove the channel resource after the workspace was deleted", func() {
+				_ = util.CreateSlackWorkspace(workspaceName, "xoxb-token", ns)
+
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.Workspace = workspaceName
+				_ = util.SubmitChannel(channelObject)
+
+				util.TryDeleteSlackWorkspace(workspaceName, ns)
+				mock.ResetCalls()
+				util.DeleteChannel(channelName, ns)
+
+				channel := &slackv1alpha1.Channel{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: channelName, Namespace: ns}, channel)
+
+				Expect(err).To(HaveOccurred())
+				Expect(mock.Calls("conversations.archive")).To(BeEmpty())
+			})
+		})
+
+		Context("With a missing workspace", func() {
+			It("should set error condition", func() {
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.Workspace = workspaceName
+				_ = util.SubmitChannel(channelObject)
+				channel := util.GetChannel(channelName, ns)
+
+				Expect(channel.Status.ID).To(BeEmpty())
+				Expect(channel.Status.Conditions[0].Reason).To(Equal("Failed"))
+			})
+		})
+	})
+
+	Describe("Creating SlackChannel resource without a valid API token", func() {
+		It("should set token unavailable condition", func() {
+			degradedReconciler := &ChannelReconciler{
+				Client:       k8sClient,
+				Scheme:       r.Scheme,
+				Log:          r.Log,
+				SlackService: slack.New("", r.Log),
+			}
+
+			channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+			Expect(k8sClient.Create(ctx, channelObject)).To(Succeed())
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: channelName, Namespace: ns}}
+			_, err := degradedReconciler.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+
+			channel := util.GetChannel(channelName, ns)
+			Expect(channel.Status.ID).To(BeEmpty())
+			Expect(meta.IsStatusConditionTrue(channel.Status.Conditions, slackv1alpha1.TokenUnavailableConditionType)).To(BeTrue())
+
+			_, err = r.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+
+			channel = util.GetChannel(channelName, ns)
+			Expect(channel.Status.ID).To(Equal(slackMock.PublicConversationID))
+			Expect(meta.FindStatusCondition(channel.Status.Conditions, slackv1alpha1.TokenUnavailableConditionType)).To(BeNil())
+		})
+	})
+
+	Describe("Adopting an existing slack channel", func() {
+		Context("With channel ID and destructive changes allowed", func() {
+			It("should set status.ID to the adopted channel ID", func() {
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.Adopt = &slackv1alpha1.ChannelAdoption{
+					ID:                      slackMock.PublicConversationID,
+					AllowDestructiveChanges: true,
+				}
+				_ = util.SubmitChannel(channelObject)
+				channel := util.GetChannel(channelName, ns)
+
+				Expect(channel.Status.ID).To(Equal(slackMock.PublicConversationID))
+				Expect(channel.Status.Adopted).To(BeTrue())
+				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+			})
+		})
+
+		Context("With channel name and destructive changes allowed", func() {
+			It("should set status.ID to the adopted channel ID", func() {
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.Adopt = &slackv1alpha1.ChannelAdoption{
+					Name:                    slackMock.AdoptableConversationName,
+					AllowDestructiveChanges: true,
+				}
+				_ = util.SubmitChannel(channelObject)
+				channel := util.GetChannel(channelName, ns)
+
+				Expect(channel.Status.ID).To(Equal(slackMock.PublicConversationID))
+				Expect(channel.Status.Adopted).To(BeTrue())
+			})
+		})
+
+		Context("With existing members that would be removed", func() {
+			It("should refuse to adopt the channel", func() {
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.Adopt = &slackv1alpha1.ChannelAdoption{
+					ID: slackMock.PublicConversationID,
+				}
+				_ = util.SubmitChannel(channelObject)
+				channel := util.GetChannel(channelName, ns)
+
+				Expect(channel.Status.ID).To(BeEmpty())
+				Expect(channel.Status.Adopted).To(BeFalse())
+				Expect(channel.Status.Conditions[0].Reason).To(Equal("Failed"))
+			})
+		})
+
+		Context("With existing members and the Additive membership policy", func() {
+			It("should adopt the channel without removing members", func() {
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.MembershipPolicy = slackv1alpha1.AdditiveMembershipPolicy
+				channelObject.Spec.Adopt = &slackv1alpha1.ChannelAdoption{
+					ID: slackMock.PublicConversationID,
+				}
+				_ = util.SubmitChannel(channelObject)
+				channel := util.GetChannel(channelName, ns)
+
+				Expect(channel.Status.ID).To(Equal(slackMock.PublicConversationID))
+				Expect(channel.Status.Adopted).To(BeTrue())
+				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+			})
+		})
+
+		Context("With mismatching privacy", func() {
+			It("should refuse to adopt the channel", func() {
+				channelObject := util.CreateSlackChannelObject(channelName, true, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.Adopt = &slackv1alpha1.ChannelAdoption{
+					ID:                      slackMock.PublicConversationID,
+					AllowDestructiveChanges: true,
+				}
+				_ = util.SubmitChannel(channelObject)
+				channel := util.GetChannel(channelName, ns)
+
+				Expect(channel.Status.ID).To(BeEmpty())
+				Expect(channel.Status.Conditions[0].Reason).To(Equal("Failed"))
 			})
 		})
 	})
@@ -127,6 +558,50 @@ var _ = Describe("ChannelController", func() {
 		})
 	})
 
+	Describe("Detecting drift of SlackChannel resource", func() {
+		Context("With ReportOnly drift policy", func() {
+			It("should report drift without updating channel", func() {
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.DriftPolicy = slackv1alpha1.ReportOnlyDriftPolicy
+				_ = util.SubmitChannel(channelObject)
+
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: channelName, Namespace: ns}}
+				_, err := r.Reconcile(context.Background(), req)
+				if err != nil {
+					Fail(err.Error())
+				}
+
+				channel := util.GetChannel(channelName, ns)
+
+				Expect(channel.Status.Drift).ToNot(BeNil())
+				Expect(channel.Status.Drift.Name).ToNot(BeNil())
+				Expect(channel.Status.Drift.Name.Actual).To(Equal(slackMock.ConversationName))
+				Expect(meta.IsStatusConditionTrue(channel.Status.Conditions, slackv1alpha1.DriftedConditionType)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("Resyncing SlackChannel resource", func() {
+		Context("With resync period", func() {
+			It("should requeue the channel after the resync period with jitter", func() {
+				resyncPeriod := 5 * time.Minute
+
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.ResyncPeriod = &metav1.Duration{Duration: resyncPeriod}
+				_ = util.SubmitChannel(channelObject)
+
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: channelName, Namespace: ns}}
+				result, err := r.Reconcile(context.Background(), req)
+				if err != nil {
+					Fail(err.Error())
+				}
+
+				Expect(result.RequeueAfter).To(BeNumerically(">=", resyncPeriod))
+				Expect(result.RequeueAfter).To(BeNumerically("<=", time.Duration(float64(resyncPeriod)*1.1)))
+			})
+		})
+	})
+
 	Describe("Deleting SlackChannel resource", func() {
 		Context("When Channel on slack was created", func() {
 			It("should remove resource and delete channel ", func() {
@@ -137,13 +612,68 @@ var _ = Describe("ChannelController", func() {
 				Expect(len(channel.Status.Conditions)).To(Equal(1))
 				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
 
+				mock.ResetCalls()
 				util.DeleteChannel(channelName, ns)
 
 				channelObject := &slackv1alpha1.Channel{}
 				err := k8sClient.Get(ctx, types.NamespacedName{Name: channelName, Namespace: ns}, channelObject)
 
 				Expect(err).To(HaveOccurred())
+				Expect(mock.Calls("conversations.archive")).To(HaveLen(1))
+			})
+		})
+
+		Context("With Retain deletion policy", func() {
+			It("should remove resource and retain channel", func() {
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.DeletionPolicy = slackv1alpha1.RetainDeletionPolicy
+				_ = util.SubmitChannel(channelObject)
+
+				mock.ResetCalls()
+				util.DeleteChannel(channelName, ns)
+
+				channel := &slackv1alpha1.Channel{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: channelName, Namespace: ns}, channel)
+
+				Expect(err).To(HaveOccurred())
+				Expect(mock.Calls("conversations.archive")).To(BeEmpty())
+				Expect(mock.Calls("conversations.rename")).To(BeEmpty())
+			})
+		})
+
+		Context("With RenameThenArchive deletion policy", func() {
+			It("should remove resource and rename channel before archiving", func() {
+				channelObject := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channelObject.Spec.DeletionPolicy = slackv1alpha1.RenameThenArchiveDeletionPolicy
+				channelObject.Spec.ArchiveSuffix = "-archived"
+				_ = util.SubmitChannel(channelObject)
+
+				mock.ResetCalls()
+				util.DeleteChannel(channelName, ns)
+
+				channel := &slackv1alpha1.Channel{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: channelName, Namespace: ns}, channel)
+
+				Expect(err).To(HaveOccurred())
+
+				renames := mock.Calls("conversations.rename")
+				Expect(renames).To(HaveLen(1))
+				Expect(renames[0].Params.Get("name")).To(Equal(channelName + "-archived"))
+				Expect(mock.Calls("conversations.archive")).To(HaveLen(1))
+
+				methods := mock.Methods()
+				Expect(indexOf(methods, "conversations.rename")).To(BeNumerically("<", indexOf(methods, "conversations.archive")))
 			})
 		})
 	})
 })
+
+// indexOf returns the index of the first occurrence of the element in the list, or -1 if it is missing
+func indexOf(list []string, element string) int {
+	for i, e := range list {
+		if e == element {
+			return i
+		}
+	}
+	return -1
+}
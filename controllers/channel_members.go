@@ -0,0 +1,201 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+const (
+	// channelMembersFromField is the field index used to look up the Channels which take members from a kind of binding
+	channelMembersFromField string = "spec.membersFrom"
+
+	roleBindingKind        string = "RoleBinding"
+	clusterRoleBindingKind string = "ClusterRoleBinding"
+)
+
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings;clusterrolebindings,verbs=get;list;watch
+
+// getChannelMembers returns the emails of the users, the user groups, the RBAC bindings and the managers of the channel
+func (r *ChannelReconciler) getChannelMembers(ctx context.Context, channel *slackv1alpha1.Channel, slackService slack.Service) ([]string, error) {
+	members, err := slackService.GetChannelMembers(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	bindingMembers, err := r.getBindingMembers(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, member := range members {
+		seen[strings.ToLower(member)] = true
+	}
+	for _, member := range append(bindingMembers, channel.Spec.Managers...) {
+		if !seen[strings.ToLower(member)] {
+			seen[strings.ToLower(member)] = true
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+// getBindingMembers returns the emails of the User subjects of the bindings selected by membersFrom
+func (r *ChannelReconciler) getBindingMembers(ctx context.Context, channel *slackv1alpha1.Channel) ([]string, error) {
+	membersFrom := channel.Spec.MembersFrom
+	if membersFrom == nil {
+		return nil, nil
+	}
+
+	var members []string
+
+	if membersFrom.RoleBindings != nil {
+		roleBindingList := &rbacv1.RoleBindingList{}
+		err := r.List(ctx, roleBindingList, client.InNamespace(channel.Namespace))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, roleBinding := range roleBindingList.Items {
+			if !membersFrom.RoleBindings.Matches(roleBinding.Name, roleBinding.RoleRef.Kind, roleBinding.RoleRef.Name) {
+				continue
+			}
+			emails, err := userEmailsOf(&roleBinding, roleBinding.Subjects)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, emails...)
+		}
+	}
+
+	if membersFrom.ClusterRoleBindings != nil {
+		clusterRoleBindingList := &rbacv1.ClusterRoleBindingList{}
+		err := r.List(ctx, clusterRoleBindingList)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, clusterRoleBinding := range clusterRoleBindingList.Items {
+			if !membersFrom.ClusterRoleBindings.Matches(clusterRoleBinding.Name, clusterRoleBinding.RoleRef.Kind, clusterRoleBinding.RoleRef.Name) {
+				continue
+			}
+			emails, err := userEmailsOf(&clusterRoleBinding, clusterRoleBinding.Subjects)
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, emails...)
+		}
+	}
+
+	return members, nil
+}
+
+// userEmailsOf returns the emails of the User subjects of a binding. The email of a subject is looked up in the
+// user emails annotation of the binding, subjects whose names aren't emails and which aren't mapped are skipped.
+func userEmailsOf(binding client.Object, subjects []rbacv1.Subject) ([]string, error) {
+	emailOf := map[string]string{}
+	if annotation, ok := binding.GetAnnotations()[slackv1alpha1.UserEmailsAnnotation]; ok {
+		err := json.Unmarshal([]byte(annotation), &emailOf)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid annotation %s of %s: %s", slackv1alpha1.UserEmailsAnnotation, binding.GetName(), err.Error())
+		}
+	}
+
+	var emails []string
+	for _, subject := range subjects {
+		if subject.Kind != rbacv1.UserKind {
+			continue
+		}
+
+		if email, ok := emailOf[subject.Name]; ok {
+			emails = append(emails, email)
+		} else if strings.Contains(subject.Name, "@") {
+			emails = append(emails, subject.Name)
+		}
+	}
+	return emails, nil
+}
+
+// indexMembersFrom returns the kinds of bindings which the Channel takes members from
+func indexMembersFrom(obj client.Object) []string {
+	membersFrom := obj.(*slackv1alpha1.Channel).Spec.MembersFrom
+	if membersFrom == nil {
+		return nil
+	}
+
+	var kinds []string
+	if membersFrom.RoleBindings != nil {
+		kinds = append(kinds, roleBindingKind)
+	}
+	if membersFrom.ClusterRoleBindings != nil {
+		kinds = append(kinds, clusterRoleBindingKind)
+	}
+	return kinds
+}
+
+// channelsOfRoleBinding returns requests for the Channels in the namespace of the RoleBinding which select it
+func (r *ChannelReconciler) channelsOfRoleBinding(obj client.Object) []reconcile.Request {
+	roleBinding := obj.(*rbacv1.RoleBinding)
+
+	channelList := &slackv1alpha1.ChannelList{}
+	err := r.List(context.Background(), channelList, client.InNamespace(roleBinding.Namespace),
+		client.MatchingFields{channelMembersFromField: roleBindingKind})
+	if err != nil {
+		r.Log.Error(err, "Error listing Channels of RoleBinding", "roleBinding", roleBinding.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, channel := range channelList.Items {
+		if channel.Spec.MembersFrom.RoleBindings.Matches(roleBinding.Name, roleBinding.RoleRef.Kind, roleBinding.RoleRef.Name) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: channel.Namespace, Name: channel.Name}})
+		}
+	}
+	return requests
+}
+
+// channelsOfClusterRoleBinding returns requests for the Channels which select the ClusterRoleBinding
+func (r *ChannelReconciler) channelsOfClusterRoleBinding(obj client.Object) []reconcile.Request {
+	clusterRoleBinding := obj.(*rbacv1.ClusterRoleBinding)
+
+	channelList := &slackv1alpha1.ChannelList{}
+	err := r.List(context.Background(), channelList, client.MatchingFields{channelMembersFromField: clusterRoleBindingKind})
+	if err != nil {
+		r.Log.Error(err, "Error listing Channels of ClusterRoleBinding", "clusterRoleBinding", clusterRoleBinding.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, channel := range channelList.Items {
+		if channel.Spec.MembersFrom.ClusterRoleBindings.Matches(clusterRoleBinding.Name, clusterRoleBinding.RoleRef.Kind, clusterRoleBinding.RoleRef.Name) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: channel.Namespace, Name: channel.Name}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,257 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+var (
+	messageFinalizer string = "slack.stakater.com/message"
+
+	// messageChannelField is the field index used to look up the Messages posted in a Channel
+	messageChannelField string = "spec.channel"
+)
+
+// MessageReconciler reconciles a Message object
+type MessageReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+
+	// APIReader reads the token secrets of SlackWorkspaces without caching the secrets of the cluster
+	APIReader client.Reader
+
+	// Workspaces caches the slack Services of the SlackWorkspaces referenced by Channels
+	Workspaces *slack.ServiceCache
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=messages,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=messages/status,verbs=get;update;patch
+
+// Reconcile loop for the Message resource
+func (r *MessageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("message", req.NamespacedName)
+
+	message := &slackv1alpha1.Message{}
+	err := r.Get(ctx, req.NamespacedName, message)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			return reconcilerUtil.DoNotRequeue()
+		}
+		// Error reading message, requeue
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	channel := &slackv1alpha1.Channel{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: message.Namespace, Name: message.Spec.Channel}, channel)
+	if err != nil && !errors.IsNotFound(err) {
+		return reconcilerUtil.ManageError(r.Client, message, err, true)
+	}
+	channelExists := err == nil
+
+	// Message is marked for deletion
+	if message.GetDeletionTimestamp() != nil {
+		log.Info("Deletion timestamp found for message " + req.Name)
+		if finalizerUtil.HasFinalizer(message, messageFinalizer) {
+			return r.finalizeMessage(ctx, message, channel, channelExists)
+		}
+		// Finalizer doesn't exist so clean up is already done
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	if !channelExists {
+		// The Message is reconciled again when the Channel is created
+		return reconcilerUtil.ManageError(r.Client, message, fmt.Errorf("Channel %s does not exist", message.Spec.Channel), false)
+	}
+	if channel.Status.ID == "" {
+		return reconcilerUtil.ManageError(r.Client, message, fmt.Errorf("Channel %s has not been created on slack yet", message.Spec.Channel), false)
+	}
+	if message.Spec.Text == "" && message.Spec.Blocks == "" {
+		return reconcilerUtil.ManageError(r.Client, message, fmt.Errorf("Either text or blocks are required"), false)
+	}
+
+	slackService, err := getChannelService(ctx, r.Client, r.APIReader, r.Workspaces, r.SlackService, channel)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, message, err, false)
+	}
+
+	// Wait for a valid token instead of failing every slack API call
+	err = slackService.TokenError()
+	if err != nil {
+		log.Info("Waiting for a valid API token", "reason", err.Error())
+		return pkgutil.ManageError(ctx, r.Client, message, err)
+	}
+
+	// Add finalizer if it doesn't exist
+	if !finalizerUtil.HasFinalizer(message, messageFinalizer) {
+		log.Info("Adding finalizer for message " + req.Name)
+
+		// Base object for patch, which patches using the merge-patch strategy with the given object as base.
+		messagePatchBase := client.MergeFrom(message.DeepCopy())
+
+		finalizerUtil.AddFinalizer(message, messageFinalizer)
+
+		err := r.Client.Patch(ctx, message, messagePatchBase)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, message, err, true)
+		}
+	}
+
+	// A message posted in another slack channel, because the Message or its Channel changed, is moved
+	if message.Status.Timestamp != "" && message.Status.ChannelID != channel.Status.ID {
+		log.Info("Deleting message from previous channel", "channelID", message.Status.ChannelID)
+		err := slackService.DeleteMessage(message.Status.ChannelID, message.Status.Timestamp)
+		if err != nil && err.Error() != "channel_not_found" && err.Error() != "message_not_found" {
+			return pkgutil.ManageError(ctx, r.Client, message, err)
+		}
+
+		// The deletion is recorded right away, so that the message is not looked for again if posting it fails
+		messagePatchBase := client.MergeFrom(message.DeepCopy())
+
+		message.Status.ChannelID = ""
+		message.Status.Timestamp = ""
+
+		err = r.Status().Patch(ctx, message, messagePatchBase)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, message, err, true)
+		}
+	}
+
+	if message.Status.Timestamp != "" {
+		if message.Status.ObservedGeneration == message.Generation {
+			return reconcilerUtil.DoNotRequeue()
+		}
+
+		log.Info("Updating message", "timestamp", message.Status.Timestamp)
+		err := slackService.UpdateMessage(channel.Status.ID, message.Status.Timestamp, message.Spec.Text, message.Spec.Blocks)
+		if err == nil {
+			return r.manageSuccess(message)
+		}
+		// A message deleted on slack is posted again
+		if err.Error() != "message_not_found" {
+			return pkgutil.ManageError(ctx, r.Client, message, err)
+		}
+	}
+
+	log.Info("Posting message", "channelID", channel.Status.ID)
+	timestamp, err := slackService.PostMessage(channel.Status.ID, message.Spec.Text, message.Spec.Blocks)
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, message, err)
+	}
+
+	// The message is recorded right away, so that it is not posted again if setting the success condition fails
+	messagePatchBase := client.MergeFrom(message.DeepCopy())
+
+	message.Status.ChannelID = channel.Status.ID
+	message.Status.Timestamp = timestamp
+
+	err = r.Status().Patch(ctx, message, messagePatchBase)
+	if err != nil {
+		log.Error(err, "Error recording posted message", "timestamp", timestamp)
+		return reconcilerUtil.ManageError(r.Client, message, err, true)
+	}
+	return r.manageSuccess(message)
+}
+
+// manageSuccess records that the spec of the Message was posted and sets the success condition
+func (r *MessageReconciler) manageSuccess(message *slackv1alpha1.Message) (ctrl.Result, error) {
+	message.Status.ObservedGeneration = message.Generation
+	return reconcilerUtil.ManageSuccess(r.Client, message)
+}
+
+func (r *MessageReconciler) finalizeMessage(ctx context.Context, message *slackv1alpha1.Message, channel *slackv1alpha1.Channel, channelExists bool) (ctrl.Result, error) {
+	log := r.Log.WithValues("channelID", message.Status.ChannelID, "timestamp", message.Status.Timestamp)
+
+	// Messages of deleted Channels are left alone, their slack channels are archived or retained
+	if message.Status.Timestamp != "" && channelExists && channel.Status.ID == message.Status.ChannelID {
+		slackService, err := getChannelService(ctx, r.Client, r.APIReader, r.Workspaces, r.SlackService, channel)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, message, err, false)
+		}
+
+		err = slackService.DeleteMessage(message.Status.ChannelID, message.Status.Timestamp)
+		if err != nil && err.Error() != "channel_not_found" && err.Error() != "is_archived" {
+			return pkgutil.ManageError(ctx, r.Client, message, err)
+		}
+	}
+
+	// Base object for patch, which patches using the merge-patch strategy with the given object as base.
+	messagePatchBase := client.MergeFrom(message.DeepCopy())
+
+	finalizerUtil.DeleteFinalizer(message, messageFinalizer)
+	log.V(1).Info("Finalizer removed for message")
+
+	err := r.Client.Patch(ctx, message, messagePatchBase)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, message, err, false)
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *MessageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &slackv1alpha1.Message{}, messageChannelField, func(obj client.Object) []string {
+		return []string{obj.(*slackv1alpha1.Message).Spec.Channel}
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.Message{}).
+		Watches(&source.Kind{Type: &slackv1alpha1.Channel{}}, handler.EnqueueRequestsFromMapFunc(r.messagesOfChannel)).
+		Complete(r)
+}
+
+// messagesOfChannel returns requests for the Messages posted in the Channel
+func (r *MessageReconciler) messagesOfChannel(channel client.Object) []reconcile.Request {
+	messageList := &slackv1alpha1.MessageList{}
+	err := r.List(context.Background(), messageList, client.InNamespace(channel.GetNamespace()),
+		client.MatchingFields{messageChannelField: channel.GetName()})
+	if err != nil {
+		r.Log.Error(err, "Error listing Messages of Channel", "channel", channel.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, message := range messageList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: message.Namespace, Name: message.Name}})
+	}
+	return requests
+}
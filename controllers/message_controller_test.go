@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/slack/mock"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("MessageController", func() {
+
+	var messageName string
+	var channelName string
+	var req reconcile.Request
+
+	BeforeEach(func() {
+		messageName = util.RandSeq(10)
+		channelName = util.RandSeq(10)
+		req = reconcile.Request{NamespacedName: types.NamespacedName{Name: messageName, Namespace: ns}}
+	})
+
+	AfterEach(func() {
+		util.TryDeleteMessage(messageName, ns)
+		util.TryDeleteChannel(channelName, ns)
+	})
+
+	Describe("Creating Message resource", func() {
+		Context("With an existing channel", func() {
+			It("should post the message and set status.timestamp", func() {
+				_ = util.CreateChannel(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channel := util.GetChannel(channelName, ns)
+				_ = util.CreateMessage(messageName, channelName, "Release 1.2.0 is rolling out", ns)
+
+				_, err := mr.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+
+				message := util.GetMessage(messageName, ns)
+				Expect(message.Status.ChannelID).To(Equal(channel.Status.ID))
+				Expect(message.Status.Timestamp).To(Equal(mock.NewMessageTimestamp))
+				Expect(message.Status.ObservedGeneration).To(Equal(message.Generation))
+				Expect(message.Finalizers).To(ContainElement(messageFinalizer))
+				Expect(message.Status.Conditions[0].Reason).To(Equal("Successful"))
+			})
+		})
+
+		Context("With a channel that does not exist", func() {
+			It("should set error condition", func() {
+				_ = util.CreateMessage(messageName, "missing-channel", "Release 1.2.0 is rolling out", ns)
+
+				_, err := mr.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+
+				message := util.GetMessage(messageName, ns)
+				Expect(message.Status.Timestamp).To(BeEmpty())
+				Expect(message.Status.Conditions[0].Reason).To(Equal("Failed"))
+			})
+		})
+	})
+
+	Describe("Updating Message resource", func() {
+		It("should update the posted message", func() {
+			_ = util.CreateChannel(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+			_ = util.CreateMessage(messageName, channelName, "Release 1.2.0 is rolling out", ns)
+
+			_, err := mr.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+
+			message := util.GetMessage(messageName, ns)
+			message.Spec.Text = "Release 1.2.0 is rolled out"
+			Expect(k8sClient.Update(ctx, message)).To(Succeed())
+
+			_, err = mr.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+
+			message = util.GetMessage(messageName, ns)
+			Expect(message.Status.Timestamp).To(Equal(mock.NewMessageTimestamp))
+			Expect(message.Status.ObservedGeneration).To(Equal(message.Generation))
+		})
+	})
+
+	Describe("Moving Message resource to another channel", func() {
+		It("should delete the message from the previous channel and post it again", func() {
+			_ = util.CreateChannel(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+			channel := util.GetChannel(channelName, ns)
+			_ = util.CreateMessage(messageName, channelName, "Release 1.2.0 is rolling out", ns)
+
+			_, err := mr.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+
+			// The message was deleted on slack before it was moved
+			message := util.GetMessage(messageName, ns)
+			message.Status.ChannelID = mock.PrivateConversationID
+			message.Status.Timestamp = "1503435958.000249"
+			Expect(k8sClient.Status().Update(ctx, message)).To(Succeed())
+
+			mock.ResetCalls()
+			_, err = mr.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+
+			message = util.GetMessage(messageName, ns)
+			Expect(message.Status.ChannelID).To(Equal(channel.Status.ID))
+			Expect(message.Status.Timestamp).To(Equal(mock.NewMessageTimestamp))
+			Expect(message.Status.Conditions[0].Reason).To(Equal("Successful"))
+
+			deletes := mock.Calls("chat.delete")
+			Expect(deletes).To(HaveLen(1))
+			Expect(deletes[0].Params.Get("channel")).To(Equal(mock.PrivateConversationID))
+			Expect(mock.Calls("chat.postMessage")).To(HaveLen(1))
+		})
+	})
+
+	Describe("Deleting Message resource", func() {
+		It("should delete the message and remove the finalizer", func() {
+			_ = util.CreateChannel(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+			_ = util.CreateMessage(messageName, channelName, "Release 1.2.0 is rolling out", ns)
+
+			_, err := mr.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(k8sClient.Delete(ctx, util.GetMessage(messageName, ns))).To(Succeed())
+
+			_, err = mr.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = k8sClient.Get(ctx, req.NamespacedName, &slackv1alpha1.Message{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
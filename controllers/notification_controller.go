@@ -0,0 +1,359 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	slackapi "github.com/slack-go/slack"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/notifications"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// notifiedConfigMapName is the name of the ConfigMap in the operator namespace which records the notifications that
+// were posted about Pods and Deployments
+const notifiedConfigMapName = "slack-operator-notified"
+
+// NotificationReconciler posts the Events, Pod failures and Deployment rollouts of a namespace in the slack channels
+// of the Channels in the namespace that enable notifications
+type NotificationReconciler struct {
+	client.Client
+	Log          logr.Logger
+	SlackService slack.Service
+
+	// APIReader reads the token secrets of SlackWorkspaces without caching the secrets of the cluster
+	APIReader client.Reader
+
+	// Namespace of the operator, which contains the notified ConfigMap
+	Namespace string
+
+	// Workspaces caches the slack Services of the SlackWorkspaces referenced by Channels
+	Workspaces *slack.ServiceCache
+
+	// Throttle keeps the same notification from being posted repeatedly
+	Throttle *notifications.Throttle
+
+	// started is the time the reconciler was set up, Events and rollouts which happened before are not posted
+	started time.Time
+
+	// notifiedMutex guards notified, which holds the data of the notified ConfigMap. It is read once and then kept up to
+	// date by recordNotified, as the reconciler is the only one writing the ConfigMap.
+	notifiedMutex sync.Mutex
+	notified      map[string]string
+}
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",namespace=system,resources=configmaps,verbs=get;create;patch
+
+// reconcileEvent posts the Event in the channels whose notifications select it
+func (r *NotificationReconciler) reconcileEvent(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	event := &corev1.Event{}
+	err := r.Get(ctx, req.NamespacedName, event)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// Events are only throttled, each occurrence updates the Event and is posted
+	return r.notify(ctx, req.Namespace, "", "", func(channelNotifications *slackv1alpha1.Notifications) ([]notifications.Notification, error) {
+		notification := notifications.ForEvent(channelNotifications.Events, event, r.started)
+		if notification == nil {
+			return nil, nil
+		}
+		return []notifications.Notification{*notification}, nil
+	})
+}
+
+// reconcilePod posts the failures of the Pod in the channels whose notifications select them
+func (r *NotificationReconciler) reconcilePod(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	recordKey := notifiedRecordKey("pod", req.NamespacedName)
+
+	pod := &corev1.Pod{}
+	err := r.Get(ctx, req.NamespacedName, pod)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return r.forgetNotified(ctx, recordKey)
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// Each failure is posted once for the lifetime of the Pod, so that a crash loop is not posted on every restart
+	return r.notify(ctx, req.Namespace, recordKey, notifications.PodKeyPrefix(pod), func(channelNotifications *slackv1alpha1.Notifications) ([]notifications.Notification, error) {
+		return notifications.ForPod(channelNotifications.PodFailures, pod)
+	})
+}
+
+// reconcileDeployment posts the rollout of the Deployment in the channels whose notifications select it
+func (r *NotificationReconciler) reconcileDeployment(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	recordKey := notifiedRecordKey("deployment", req.NamespacedName)
+
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, req.NamespacedName, deployment)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return r.forgetNotified(ctx, recordKey)
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// Each rollout is posted once per generation and outcome
+	return r.notify(ctx, req.Namespace, recordKey, notifications.DeploymentKeyPrefix(deployment), func(channelNotifications *slackv1alpha1.Notifications) ([]notifications.Notification, error) {
+		notification, err := notifications.ForDeployment(channelNotifications.DeploymentRollouts, deployment, r.started)
+		if notification == nil {
+			return nil, err
+		}
+		return []notifications.Notification{*notification}, nil
+	})
+}
+
+// notify posts the notifications returned for each Channel of the namespace that enables notifications and has a
+// slack channel, unless they are throttled. If recordKey is given, the posted notifications are recorded under it in the
+// notified ConfigMap and not posted again, only those whose keys have the prefix are kept.
+func (r *NotificationReconciler) notify(ctx context.Context, namespace string, recordKey string, keyPrefix string,
+	notificationsOf func(*slackv1alpha1.Notifications) ([]notifications.Notification, error)) (ctrl.Result, error) {
+
+	channelList := &slackv1alpha1.ChannelList{}
+	err := r.List(ctx, channelList, client.InNamespace(namespace))
+	if err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	notified := notifications.Notified{}
+	if recordKey != "" {
+		notified, err = r.getNotified(ctx, recordKey)
+		if err != nil {
+			return reconcilerUtil.RequeueWithError(err)
+		}
+	}
+
+	for i := range channelList.Items {
+		channel := &channelList.Items[i]
+		if channel.Spec.Notifications == nil || channel.Status.ID == "" || channel.DeletionTimestamp != nil {
+			continue
+		}
+		log := r.Log.WithValues("channel", channel.Namespace+"/"+channel.Name)
+
+		channelNotifications, err := notificationsOf(channel.Spec.Notifications)
+		if err != nil {
+			log.Error(err, "Error selecting notifications")
+			continue
+		}
+		if len(channelNotifications) == 0 {
+			continue
+		}
+
+		slackService, err := getChannelService(ctx, r.Client, r.APIReader, r.Workspaces, r.SlackService, channel)
+		if err != nil {
+			log.Error(err, "Error getting slack workspace of channel")
+			continue
+		}
+
+		throttlePeriod, limit := throttleOf(channel.Spec.Notifications)
+		for _, notification := range channelNotifications {
+			if notified.Has(channel.Status.ID, notification.Key) || !r.Throttle.Allow(channel.Status.ID, notification.Key, throttlePeriod, limit) {
+				continue
+			}
+
+			_, err := slackService.PostMessage(channel.Status.ID, notification.Text, "")
+			if err != nil {
+				r.Throttle.Forget(channel.Status.ID, notification.Key)
+
+				// The notifications posted so far are recorded, so that retrying doesn't post them again
+				recordErr := r.recordNotified(ctx, recordKey, notified, keyPrefix)
+				if recordErr != nil {
+					log.Error(recordErr, "Error recording posted notifications")
+				}
+
+				var rateLimitedError *slackapi.RateLimitedError
+				if goerrors.As(err, &rateLimitedError) {
+					return reconcilerUtil.RequeueAfter(rateLimitedError.RetryAfter)
+				}
+				return reconcilerUtil.RequeueWithError(err)
+			}
+			notified.Add(channel.Status.ID, notification.Key)
+			log.Info("Posted notification", "key", notification.Key)
+		}
+	}
+
+	err = r.recordNotified(ctx, recordKey, notified, keyPrefix)
+	if err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// forgetNotified removes the notifications recorded under the key, after the object was deleted
+func (r *NotificationReconciler) forgetNotified(ctx context.Context, recordKey string) (ctrl.Result, error) {
+	err := r.recordNotified(ctx, recordKey, notifications.Notified{}, "")
+	if err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// notifiedRecordKey returns the key of the notified ConfigMap under which the notifications posted about the object
+// of the kind are recorded
+func notifiedRecordKey(kind string, name types.NamespacedName) string {
+	return kind + "." + name.Namespace + "." + name.Name
+}
+
+// getNotified returns the notifications recorded under the key in the notified ConfigMap
+func (r *NotificationReconciler) getNotified(ctx context.Context, recordKey string) (notifications.Notified, error) {
+	r.notifiedMutex.Lock()
+	defer r.notifiedMutex.Unlock()
+
+	err := r.loadNotified(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return notifications.ParseNotified(r.notified[recordKey]), nil
+}
+
+// loadNotified reads the notified ConfigMap, unless it was read before
+func (r *NotificationReconciler) loadNotified(ctx context.Context) error {
+	if r.notified != nil {
+		return nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: notifiedConfigMapName}, configMap)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	r.notified = map[string]string{}
+	for key, value := range configMap.Data {
+		r.notified[key] = value
+	}
+	return nil
+}
+
+// recordNotified patches the notifications recorded under the key in the notified ConfigMap if they changed, and
+// creates the ConfigMap if it doesn't exist yet
+func (r *NotificationReconciler) recordNotified(ctx context.Context, recordKey string, notified notifications.Notified, keyPrefix string) error {
+	if recordKey == "" {
+		return nil
+	}
+
+	r.notifiedMutex.Lock()
+	defer r.notifiedMutex.Unlock()
+
+	err := r.loadNotified(ctx)
+	if err != nil {
+		return err
+	}
+
+	value := notified.Value(keyPrefix)
+	if r.notified[recordKey] == value {
+		return nil
+	}
+
+	// Only the key is patched, a null value removes it from the ConfigMap
+	var patchValue interface{}
+	if value != "" {
+		patchValue = value
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"data": map[string]interface{}{recordKey: patchValue},
+	})
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: r.Namespace, Name: notifiedConfigMapName}}
+	err = r.Patch(ctx, configMap, client.RawPatch(types.MergePatchType, patch))
+	if errors.IsNotFound(err) {
+		err = nil
+		if value != "" {
+			configMap.Data = map[string]string{recordKey: value}
+			err = r.Create(ctx, configMap)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		delete(r.notified, recordKey)
+	} else {
+		r.notified[recordKey] = value
+	}
+	return nil
+}
+
+// throttleOf returns the throttle period and the limit of the notifications, or their defaults if they are empty
+func throttleOf(channelNotifications *slackv1alpha1.Notifications) (time.Duration, int) {
+	throttlePeriod := notifications.DefaultThrottlePeriod
+	if channelNotifications.ThrottlePeriod != nil {
+		throttlePeriod = channelNotifications.ThrottlePeriod.Duration
+	}
+
+	limit := notifications.DefaultLimit
+	if channelNotifications.Limit > 0 {
+		limit = int(channelNotifications.Limit)
+	}
+	return throttlePeriod, limit
+}
+
+// SetupWithManager sets up a controller for each kind of object that notifications are posted for
+func (r *NotificationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.started = time.Now()
+	if r.Throttle == nil {
+		r.Throttle = notifications.NewThrottle()
+	}
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		Named("notification-event").
+		For(&corev1.Event{}).
+		Complete(reconcile.Func(r.reconcileEvent))
+	if err != nil {
+		return err
+	}
+
+	err = ctrl.NewControllerManagedBy(mgr).
+		Named("notification-pod").
+		For(&corev1.Pod{}).
+		Complete(reconcile.Func(r.reconcilePod))
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("notification-deployment").
+		For(&appsv1.Deployment{}).
+		Complete(reconcile.Func(r.reconcileDeployment))
+}
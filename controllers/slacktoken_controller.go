@@ -0,0 +1,201 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/alertmanager"
+	"github.com/stakater/slack-operator/pkg/config"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// tokenEventBufferSize is the number of Channels that can be enqueued before the reconciler blocks
+const tokenEventBufferSize int = 100
+
+// SlackTokenReconciler loads the API token of the default slack workspace from its secret and reloads it when the
+// secret changes. Until a valid token is loaded the SlackService is degraded, once it is loaded all Channels of the
+// default workspace are reconciled.
+type SlackTokenReconciler struct {
+	client.Client
+	Log logr.Logger
+
+	// Namespace of the secret containing the API token, its name and keys are read from the operator config
+	Namespace string
+
+	SlackService *slack.SlackService
+
+	// OnSigningSecret is called with the signing secret of the slack app when the secret contains one
+	OnSigningSecret func(string)
+
+	// OnAlertmanagerCredentials is called with the credentials of the Alertmanager webhooks the secret contains
+	OnAlertmanagerCredentials func(alertmanager.Credentials)
+
+	secretReader client.Reader
+	events       chan event.GenericEvent
+	triggers     chan event.GenericEvent
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile loop for the secret containing the API token
+func (r *SlackTokenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("secret", req.NamespacedName)
+	slackConfig := config.Get().Slack
+
+	if req.Name != slackConfig.APIToken.SecretName {
+		// The secret name was changed in the config, the new secret has been enqueued by Reload
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	secret := &corev1.Secret{}
+	err := r.secretReader.Get(ctx, req.NamespacedName, secret)
+	if err != nil {
+		if errors.IsNotFound(err) && r.SlackService.TokenError() == nil {
+			log.Info("Secret containing the API token was deleted, keeping the current token")
+			return reconcilerUtil.DoNotRequeue()
+		}
+		// Retry with backoff until the secret is created, e.g. synced by an external secrets operator
+		log.Info("Waiting for secret containing the API token", "reason", err.Error())
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if signingSecret, ok := secret.Data[slackConfig.SigningSecretKey]; ok && r.OnSigningSecret != nil {
+		r.OnSigningSecret(string(signingSecret))
+	}
+
+	if r.OnAlertmanagerCredentials != nil {
+		alertmanagerConfig := config.Get().Alertmanager
+		r.OnAlertmanagerCredentials(alertmanager.Credentials{
+			BearerToken: string(secret.Data[alertmanagerConfig.BearerTokenKey]),
+			Username:    string(secret.Data[alertmanagerConfig.UsernameKey]),
+			Password:    string(secret.Data[alertmanagerConfig.PasswordKey]),
+		})
+	}
+
+	token, ok := secret.Data[slackConfig.APIToken.Key]
+	if !ok {
+		return reconcilerUtil.RequeueWithError(fmt.Errorf("secret %s did not contain key %s", req.Name, slackConfig.APIToken.Key))
+	}
+
+	degraded := r.SlackService.TokenError() != nil
+
+	err = r.SlackService.SetToken(string(token))
+	if err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	log.Info("Loaded API token")
+
+	if degraded {
+		return r.enqueueChannels(ctx)
+	}
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// enqueueChannels reconciles the Channels of the default workspace, which were waiting for a valid token
+func (r *SlackTokenReconciler) enqueueChannels(ctx context.Context) (ctrl.Result, error) {
+	channelList := &slackv1alpha1.ChannelList{}
+	err := r.List(ctx, channelList)
+	if err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	for i := range channelList.Items {
+		if channelList.Items[i].Spec.Workspace != "" {
+			continue
+		}
+		select {
+		case r.events <- event.GenericEvent{Object: &channelList.Items[i]}:
+		case <-ctx.Done():
+			return reconcilerUtil.RequeueWithError(ctx.Err())
+		}
+	}
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// Events returns the channel on which the Channels to reconcile after a valid token was loaded are sent
+func (r *SlackTokenReconciler) Events() <-chan event.GenericEvent {
+	if r.events == nil {
+		r.events = make(chan event.GenericEvent, tokenEventBufferSize)
+	}
+	return r.events
+}
+
+// Reload reloads the API token, e.g. after the secret name or keys were changed in the operator config
+func (r *SlackTokenReconciler) Reload() {
+	secretName := config.Get().Slack.APIToken.SecretName
+	select {
+	case r.triggers <- event.GenericEvent{Object: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: r.Namespace}}}:
+	default:
+		// A reload is already pending
+	}
+}
+
+// SetupWithManager - Controller-Manager binding configuration. The secret is watched through a cache of the
+// operator namespace, so that the secrets of the whole cluster aren't cached.
+func (r *SlackTokenReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	secretCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:    mgr.GetScheme(),
+		Mapper:    mgr.GetRESTMapper(),
+		Namespace: r.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = mgr.Add(secretCache)
+	if err != nil {
+		return err
+	}
+	r.secretReader = secretCache
+	_ = r.Events()
+
+	c, err := controller.New("slacktoken", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(source.NewKindWithCache(&corev1.Secret{}, secretCache), &handler.EnqueueRequestForObject{},
+		predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetName() == config.Get().Slack.APIToken.SecretName && obj.GetNamespace() == r.Namespace
+		}))
+	if err != nil {
+		return err
+	}
+
+	// Load the token on start, even if the secret doesn't exist yet
+	r.triggers = make(chan event.GenericEvent, 1)
+	r.Reload()
+
+	return c.Watch(&source.Channel{Source: r.triggers}, &handler.EnqueueRequestForObject{})
+}
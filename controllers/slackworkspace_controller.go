@@ -0,0 +1,109 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	secretsUtil "github.com/stakater/operator-utils/util/secrets"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/config"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// SlackWorkspaceReconciler reconciles a SlackWorkspace object
+type SlackWorkspaceReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+
+	// APIReader reads the token secrets of SlackWorkspaces without caching the secrets of the cluster
+	APIReader client.Reader
+
+	// Workspaces caches the slack Services of the SlackWorkspaces, shared with the ChannelReconciler
+	Workspaces *slack.ServiceCache
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=slackworkspaces,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=slackworkspaces/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list
+
+// Reconcile loop for the SlackWorkspace resource
+func (r *SlackWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("workspace", req.NamespacedName)
+
+	workspace := &slackv1alpha1.SlackWorkspace{}
+	err := r.Get(ctx, req.NamespacedName, workspace)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			r.Workspaces.Delete(req.NamespacedName.String())
+			return reconcilerUtil.DoNotRequeue()
+		}
+		// Error reading workspace, requeue
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	slackService, err := getWorkspaceService(r.APIReader, r.Workspaces, workspace)
+	if err != nil {
+		log.Error(err, "Error reading token of workspace")
+		return reconcilerUtil.ManageError(r.Client, workspace, err, false)
+	}
+
+	team, err := slackService.AuthTest()
+	if err != nil {
+		err = fmt.Errorf("Token of workspace is invalid: %s", err.Error())
+		return reconcilerUtil.ManageError(r.Client, workspace, err, false)
+	}
+
+	workspace.Status.TeamID = team.TeamID
+	workspace.Status.Team = team.Team
+
+	return reconcilerUtil.ManageSuccess(r.Client, workspace)
+}
+
+// getWorkspaceService returns the slack Service of the workspace, created with the token from its secret
+func getWorkspaceService(apiReader client.Reader, workspaces *slack.ServiceCache, workspace *slackv1alpha1.SlackWorkspace) (slack.Service, error) {
+	secretRef := workspace.Spec.TokenSecretRef
+	if secretRef.Key == "" {
+		secretRef.Key = config.SlackAPITokenSecretKey
+	}
+
+	token, err := secretsUtil.LoadSecretData(apiReader, secretRef.Name, workspace.Namespace, secretRef.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	key := client.ObjectKeyFromObject(workspace).String()
+	return workspaces.Get(key, token), nil
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *SlackWorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.SlackWorkspace{}).
+		Complete(r)
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("SlackWorkspaceController", func() {
+
+	var workspaceName string
+
+	BeforeEach(func() {
+		workspaceName = util.RandSeq(10)
+	})
+
+	AfterEach(func() {
+		util.TryDeleteSlackWorkspace(workspaceName, ns)
+	})
+
+	Describe("Creating SlackWorkspace resource", func() {
+		Context("With a valid token", func() {
+			It("should set the team of the token in status", func() {
+				_ = util.CreateSlackWorkspace(workspaceName, "xoxb-token", ns)
+
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: workspaceName, Namespace: ns}}
+				_, err := wr.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+
+				workspace := util.GetSlackWorkspace(workspaceName, ns)
+				Expect(workspace.Status.TeamID).ToNot(BeEmpty())
+				Expect(workspace.Status.Conditions[0].Reason).To(Equal("Successful"))
+			})
+		})
+
+		Context("Without token secret", func() {
+			It("should set error condition", func() {
+				workspace := &slackv1alpha1.SlackWorkspace{
+					ObjectMeta: metav1.ObjectMeta{Name: workspaceName, Namespace: ns},
+					Spec: slackv1alpha1.SlackWorkspaceSpec{
+						TokenSecretRef: slackv1alpha1.SecretKeyReference{Name: "nonexistent-secret"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, workspace)).To(Succeed())
+
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: workspaceName, Namespace: ns}}
+				_, err := wr.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+
+				workspace = util.GetSlackWorkspace(workspaceName, ns)
+				Expect(workspace.Status.Conditions[0].Reason).To(Equal("Failed"))
+			})
+		})
+	})
+})
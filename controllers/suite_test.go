@@ -48,6 +48,9 @@ var testEnv *envtest.Environment
 
 var ctx context.Context
 var r *ChannelReconciler
+var wr *SlackWorkspaceReconciler
+var ur *UserGroupReconciler
+var mr *MessageReconciler
 var util *controllerUtil.TestUtil
 var ns = "test"
 
@@ -91,14 +94,44 @@ var _ = BeforeSuite(func(done Done) {
 
 	ctx = context.Background()
 
+	workspaces := slack.NewServiceCache(func(token string) slack.Service {
+		return slack.NewMockService(log.WithName("SlackTestServer"))
+	})
+
 	r = &ChannelReconciler{
 		Client:       k8sClient,
 		Scheme:       scheme.Scheme,
 		Log:          log.WithName("Reconciler"),
 		SlackService: slack.NewMockService(log.WithName("SlackTestServer")),
+		APIReader:    k8sClient,
+		Workspaces:   workspaces,
 	}
 	Expect(r).ToNot((BeNil()))
 
+	wr = &SlackWorkspaceReconciler{
+		Client:     k8sClient,
+		Scheme:     scheme.Scheme,
+		Log:        log.WithName("WorkspaceReconciler"),
+		APIReader:  k8sClient,
+		Workspaces: workspaces,
+	}
+
+	ur = &UserGroupReconciler{
+		Client:       k8sClient,
+		Scheme:       scheme.Scheme,
+		Log:          log.WithName("UserGroupReconciler"),
+		SlackService: slack.NewMockService(log.WithName("SlackTestServer")),
+	}
+
+	mr = &MessageReconciler{
+		Client:       k8sClient,
+		Scheme:       scheme.Scheme,
+		Log:          log.WithName("MessageReconciler"),
+		SlackService: slack.NewMockService(log.WithName("SlackTestServer")),
+		APIReader:    k8sClient,
+		Workspaces:   workspaces,
+	}
+
 	util = controllerUtil.New(ctx, k8sClient, r)
 	Expect(util).ToNot(BeNil())
 
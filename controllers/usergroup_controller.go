@@ -0,0 +1,315 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	slackapi "github.com/slack-go/slack"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/config"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+var (
+	userGroupFinalizer string = "slack.stakater.com/usergroup"
+
+	// userGroupChannelsField is the field index used to look up the UserGroups whose default channels include a Channel
+	userGroupChannelsField string = "spec.channels"
+)
+
+// UserGroupReconciler reconciles a UserGroup object. User groups are managed in the operator's default workspace.
+type UserGroupReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=usergroups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=usergroups/status,verbs=get;update;patch
+
+// Reconcile loop for the UserGroup resource
+func (r *UserGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("userGroup", req.NamespacedName)
+
+	userGroup := &slackv1alpha1.UserGroup{}
+	err := r.Get(ctx, req.NamespacedName, userGroup)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			return reconcilerUtil.DoNotRequeue()
+		}
+		// Error reading user group, requeue
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// UserGroup is marked for deletion
+	if userGroup.GetDeletionTimestamp() != nil {
+		log.Info("Deletion timestamp found for user group " + req.Name)
+		if finalizerUtil.HasFinalizer(userGroup, userGroupFinalizer) {
+			return r.finalizeUserGroup(userGroup)
+		}
+		// Finalizer doesn't exist so clean up is already done
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// Wait for a valid token instead of failing every slack API call
+	err = r.SlackService.TokenError()
+	if err != nil {
+		log.Info("Waiting for a valid API token", "reason", err.Error())
+		return pkgutil.ManageError(ctx, r.Client, userGroup, err)
+	}
+
+	// Add finalizer if it doesn't exist
+	if !finalizerUtil.HasFinalizer(userGroup, userGroupFinalizer) {
+		log.Info("Adding finalizer for user group " + req.Name)
+
+		// Base object for patch, which patches using the merge-patch strategy with the given object as base.
+		userGroupPatchBase := client.MergeFrom(userGroup.DeepCopy())
+
+		finalizerUtil.AddFinalizer(userGroup, userGroupFinalizer)
+
+		err := r.Client.Patch(ctx, userGroup, userGroupPatchBase)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, userGroup, err, true)
+		}
+	}
+
+	channelIDs, err := r.getChannelIDs(ctx, userGroup)
+	if err != nil {
+		// The UserGroup is reconciled again when the Channel is created
+		return reconcilerUtil.ManageError(r.Client, userGroup, err, false)
+	}
+
+	spec := userGroup.Spec
+
+	if userGroup.Status.ID == "" {
+		log.Info("Creating new user group", "handle", spec.Handle)
+
+		userGroupID, err := r.SlackService.CreateUserGroup(spec.Handle, spec.Name, spec.Description, channelIDs)
+		if err != nil {
+			if err.Error() != "name_already_exists" && err.Error() != "handle_already_exists" {
+				return pkgutil.ManageError(ctx, r.Client, userGroup, err)
+			}
+
+			// Existing user groups are only managed when asked for, taking them over replaces their members
+			if !spec.Adopt {
+				err = fmt.Errorf("Slack user group @%s already exists, set 'adopt' to manage it with this UserGroup", spec.Handle)
+				return reconcilerUtil.ManageError(r.Client, userGroup, err, false)
+			}
+
+			existingUserGroup, err := r.SlackService.GetUserGroupByHandle(spec.Handle)
+			if err != nil {
+				return pkgutil.ManageError(ctx, r.Client, userGroup, err)
+			}
+
+			err = r.checkNotManaged(ctx, userGroup, existingUserGroup.ID)
+			if err != nil {
+				return reconcilerUtil.ManageError(r.Client, userGroup, err, false)
+			}
+
+			log.Info("Adopting existing user group", "userGroupID", existingUserGroup.ID)
+			userGroupID = &existingUserGroup.ID
+		}
+
+		// Base object for patch, which patches using the merge-patch strategy with the given object as base.
+		userGroupPatchBase := client.MergeFrom(userGroup.DeepCopy())
+
+		userGroup.Status.ID = *userGroupID
+
+		err = r.Status().Patch(ctx, userGroup, userGroupPatchBase)
+		if err != nil {
+			log.Error(err, "Failed to update UserGroup status")
+			return reconcilerUtil.ManageError(r.Client, userGroup, err, true)
+		}
+	}
+
+	existingUserGroup, err := r.SlackService.GetUserGroup(userGroup.Status.ID)
+	if err != nil {
+		return pkgutil.ManageError(ctx, r.Client, userGroup, err)
+	}
+
+	return r.updateUserGroup(ctx, userGroup, existingUserGroup, channelIDs)
+}
+
+// checkNotManaged returns an error if another UserGroup already manages the slack user group, so that two UserGroups
+// don't overwrite each other's members
+func (r *UserGroupReconciler) checkNotManaged(ctx context.Context, userGroup *slackv1alpha1.UserGroup, userGroupID string) error {
+	userGroups := &slackv1alpha1.UserGroupList{}
+	err := r.List(ctx, userGroups)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range userGroups.Items {
+		if other.Status.ID == userGroupID && (other.Namespace != userGroup.Namespace || other.Name != userGroup.Name) {
+			return fmt.Errorf("Slack user group @%s is already managed by UserGroup %s/%s", userGroup.Spec.Handle, other.Namespace, other.Name)
+		}
+	}
+	return nil
+}
+
+// updateUserGroup only calls the slack API for the settings that differ, to save on rate limited requests
+func (r *UserGroupReconciler) updateUserGroup(ctx context.Context, userGroup *slackv1alpha1.UserGroup, existingUserGroup *slackapi.UserGroup, channelIDs []string) (ctrl.Result, error) {
+	log := r.Log.WithValues("userGroupID", existingUserGroup.ID)
+	spec := userGroup.Spec
+
+	// Disabled user groups have a deletion date
+	if existingUserGroup.DateDelete != 0 {
+		log.Info("Enabling disabled user group")
+		err := r.SlackService.EnableUserGroup(existingUserGroup.ID)
+		if err != nil {
+			return pkgutil.ManageError(ctx, r.Client, userGroup, err)
+		}
+	}
+
+	// Slack keeps the description and default channels if they are left empty
+	changed := existingUserGroup.Handle != spec.Handle || existingUserGroup.Name != spec.Name ||
+		(spec.Description != "" && existingUserGroup.Description != spec.Description) ||
+		(len(channelIDs) > 0 && !pkgutil.SameElements(existingUserGroup.Prefs.Channels, channelIDs))
+
+	if changed {
+		log.Info("Updating user group details")
+		err := r.SlackService.UpdateUserGroup(existingUserGroup.ID, spec.Handle, spec.Name, spec.Description, channelIDs)
+		if err != nil {
+			return pkgutil.ManageError(ctx, r.Client, userGroup, err)
+		}
+	}
+
+	errorlist := r.SlackService.SetUserGroupMembers(existingUserGroup, spec.Users)
+	if len(errorlist) > 0 {
+		log.Error(pkgutil.MapErrorListToError(errorlist), "Error setting members of user group")
+		return pkgutil.ManageError(ctx, r.Client, userGroup, pkgutil.MapErrorListToError(errorlist))
+	}
+
+	result, err := reconcilerUtil.ManageSuccess(r.Client, userGroup)
+	if err != nil {
+		return result, err
+	}
+
+	// Changes made to the user group on slack are overwritten on the next resync
+	resyncPeriod := config.Get().ResyncPeriod.Duration
+	if resyncPeriod <= 0 {
+		return reconcilerUtil.DoNotRequeue()
+	}
+	return reconcilerUtil.RequeueAfter(wait.Jitter(resyncPeriod, resyncJitterFactor))
+}
+
+// getChannelIDs returns the IDs of the slack channels of the default channels of the UserGroup
+func (r *UserGroupReconciler) getChannelIDs(ctx context.Context, userGroup *slackv1alpha1.UserGroup) ([]string, error) {
+	var channelIDs []string
+
+	for _, name := range userGroup.Spec.Channels {
+		channel := &slackv1alpha1.Channel{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: userGroup.Namespace, Name: name}, channel)
+		if err != nil {
+			return nil, err
+		}
+
+		if channel.Spec.Workspace != "" {
+			return nil, fmt.Errorf("Channel %s belongs to the SlackWorkspace %s, user groups can only use channels of the default workspace", name, channel.Spec.Workspace)
+		}
+		if channel.Status.ID == "" {
+			return nil, fmt.Errorf("Channel %s has not been created on slack yet", name)
+		}
+		channelIDs = append(channelIDs, channel.Status.ID)
+	}
+
+	return channelIDs, nil
+}
+
+func (r *UserGroupReconciler) finalizeUserGroup(userGroup *slackv1alpha1.UserGroup) (ctrl.Result, error) {
+	userGroupID := userGroup.Status.ID
+	log := r.Log.WithValues("userGroupID", userGroupID)
+
+	if userGroupID != "" {
+		// The user group is disabled once a valid token was loaded
+		err := r.SlackService.TokenError()
+		if err != nil {
+			log.Info("Waiting for a valid API token to finalize user group", "reason", err.Error())
+			return pkgutil.ManageError(context.Background(), r.Client, userGroup, err)
+		}
+
+		err = r.SlackService.DisableUserGroup(userGroupID)
+
+		if err != nil && err.Error() != "no_such_subteam" {
+			return pkgutil.ManageError(context.Background(), r.Client, userGroup, err)
+		}
+	}
+
+	// Base object for patch, which patches using the merge-patch strategy with the given object as base.
+	userGroupPatchBase := client.MergeFrom(userGroup.DeepCopy())
+
+	finalizerUtil.DeleteFinalizer(userGroup, userGroupFinalizer)
+	log.V(1).Info("Finalizer removed for user group")
+
+	err := r.Client.Patch(context.Background(), userGroup, userGroupPatchBase)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, userGroup, err, false)
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *UserGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &slackv1alpha1.UserGroup{}, userGroupChannelsField, func(obj client.Object) []string {
+		return obj.(*slackv1alpha1.UserGroup).Spec.Channels
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.UserGroup{}).
+		Watches(&source.Kind{Type: &slackv1alpha1.Channel{}}, handler.EnqueueRequestsFromMapFunc(r.userGroupsOfChannel)).
+		Complete(r)
+}
+
+// userGroupsOfChannel returns requests for the UserGroups whose default channels include the Channel
+func (r *UserGroupReconciler) userGroupsOfChannel(channel client.Object) []reconcile.Request {
+	userGroupList := &slackv1alpha1.UserGroupList{}
+	err := r.List(context.Background(), userGroupList, client.InNamespace(channel.GetNamespace()),
+		client.MatchingFields{userGroupChannelsField: channel.GetName()})
+	if err != nil {
+		r.Log.Error(err, "Error listing UserGroups of Channel", "channel", channel.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, userGroup := range userGroupList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: userGroup.Namespace, Name: userGroup.Name}})
+	}
+	return requests
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/slack/mock"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("UserGroupController", func() {
+
+	var userGroupName string
+	var req reconcile.Request
+
+	BeforeEach(func() {
+		userGroupName = util.RandSeq(10)
+		req = reconcile.Request{NamespacedName: types.NamespacedName{Name: userGroupName, Namespace: ns}}
+	})
+
+	AfterEach(func() {
+		util.TryDeleteUserGroup(userGroupName, ns)
+	})
+
+	Describe("Creating UserGroup resource", func() {
+		Context("With a new handle", func() {
+			It("should set status.ID to the created user group ID", func() {
+				_ = util.CreateUserGroup(userGroupName, "ghostbusters-oncall", []string{mock.ExistingUserEmail}, nil, ns)
+
+				_, err := ur.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+
+				userGroup := util.GetUserGroup(userGroupName, ns)
+				Expect(userGroup.Status.ID).To(Equal(mock.NewUserGroupID))
+				Expect(userGroup.Finalizers).To(ContainElement(userGroupFinalizer))
+				Expect(userGroup.Status.Conditions[0].Reason).To(Equal("Successful"))
+			})
+		})
+
+		Context("With the handle of an existing user group", func() {
+			It("should set error condition instead of taking over the user group", func() {
+				_ = util.CreateUserGroup(userGroupName, mock.UserGroupHandle, []string{mock.ExistingUserEmail}, nil, ns)
+
+				mock.ResetCalls()
+				_, err := ur.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+
+				userGroup := util.GetUserGroup(userGroupName, ns)
+				Expect(userGroup.Status.ID).To(BeEmpty())
+				Expect(userGroup.Status.Conditions[0].Reason).To(Equal("Failed"))
+				Expect(mock.Calls("usergroups.users.update")).To(BeEmpty())
+			})
+
+			It("should set status.ID to the existing user group ID when adopting it", func() {
+				userGroup := util.CreateUserGroup(userGroupName, mock.UserGroupHandle, []string{mock.ExistingUserEmail}, nil, ns)
+				userGroup.Spec.Adopt = true
+				Expect(k8sClient.Update(ctx, userGroup)).To(Succeed())
+
+				_, err := ur.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+
+				userGroup = util.GetUserGroup(userGroupName, ns)
+				Expect(userGroup.Status.ID).To(Equal(mock.UserGroupID))
+			})
+
+			It("should refuse to adopt a user group managed by another UserGroup", func() {
+				otherName := util.RandSeq(10)
+				other := util.CreateUserGroup(otherName, mock.UserGroupHandle, []string{mock.ExistingUserEmail}, nil, ns)
+				defer util.TryDeleteUserGroup(otherName, ns)
+				other.Status.ID = mock.UserGroupID
+				Expect(k8sClient.Status().Update(ctx, other)).To(Succeed())
+
+				userGroup := util.CreateUserGroup(userGroupName, mock.UserGroupHandle, []string{mock.ExistingUserEmail}, nil, ns)
+				userGroup.Spec.Adopt = true
+				Expect(k8sClient.Update(ctx, userGroup)).To(Succeed())
+
+				_, err := ur.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+
+				userGroup = util.GetUserGroup(userGroupName, ns)
+				Expect(userGroup.Status.ID).To(BeEmpty())
+				Expect(userGroup.Status.Conditions[0].Message).To(ContainSubstring("already managed"))
+			})
+		})
+
+		Context("With a user that does not exist", func() {
+			It("should set error condition", func() {
+				_ = util.CreateUserGroup(userGroupName, "ghostbusters-oncall", []string{"nonexistent@slack.com"}, nil, ns)
+
+				_, err := ur.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+
+				userGroup := util.GetUserGroup(userGroupName, ns)
+				Expect(userGroup.Status.Conditions[0].Reason).To(Equal("Failed"))
+				Expect(userGroup.Status.Conditions[0].Message).To(Equal("Error fetching user by Email nonexistent@slack.com"))
+			})
+		})
+
+		Context("With a default channel that does not exist", func() {
+			It("should set error condition", func() {
+				_ = util.CreateUserGroup(userGroupName, "ghostbusters-oncall", []string{mock.ExistingUserEmail}, []string{"missing-channel"}, ns)
+
+				_, err := ur.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+
+				userGroup := util.GetUserGroup(userGroupName, ns)
+				Expect(userGroup.Status.ID).To(BeEmpty())
+				Expect(userGroup.Status.Conditions[0].Reason).To(Equal("Failed"))
+			})
+		})
+
+		Context("With an existing default channel", func() {
+			It("should create the user group", func() {
+				channelName := util.RandSeq(10)
+				_ = util.CreateChannel(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				defer util.TryDeleteChannel(channelName, ns)
+
+				_ = util.CreateUserGroup(userGroupName, "ghostbusters-oncall", []string{mock.ExistingUserEmail}, []string{channelName}, ns)
+
+				_, err := ur.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+
+				userGroup := util.GetUserGroup(userGroupName, ns)
+				Expect(userGroup.Status.ID).To(Equal(mock.NewUserGroupID))
+				Expect(userGroup.Status.Conditions[0].Reason).To(Equal("Successful"))
+			})
+		})
+	})
+
+	Describe("Deleting UserGroup resource", func() {
+		It("should disable the user group and remove the finalizer", func() {
+			_ = util.CreateUserGroup(userGroupName, "ghostbusters-oncall", []string{mock.ExistingUserEmail}, nil, ns)
+
+			_, err := ur.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(k8sClient.Delete(ctx, util.GetUserGroup(userGroupName, ns))).To(Succeed())
+
+			_, err = ur.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = k8sClient.Get(ctx, req.NamespacedName, &slackv1alpha1.UserGroup{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -9,6 +9,7 @@ import (
 	ginkgo "github.com/onsi/ginkgo"
 	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
 	mockdata "github.com/stakater/slack-operator/pkg/slack/mock"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,13 +35,18 @@ func New(ctx context.Context, k8sClient client.Client, r reconcile.Reconciler) *
 // CreateChannel creates and submits a Slack Channel object to the kubernetes server
 func (t *TestUtil) CreateChannel(name string, isPrivate bool, topic string, description string, users []string, namespace string) *slackv1alpha1.Channel {
 	channelObject := t.CreateSlackChannelObject(name, isPrivate, topic, description, users, namespace)
+	return t.SubmitChannel(channelObject)
+}
+
+// SubmitChannel submits the given Slack Channel object to the kubernetes server and reconciles it
+func (t *TestUtil) SubmitChannel(channelObject *slackv1alpha1.Channel) *slackv1alpha1.Channel {
 	err := t.k8sClient.Create(t.ctx, channelObject)
 
 	if err != nil {
 		ginkgo.Fail(err.Error())
 	}
 
-	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: channelObject.Name, Namespace: channelObject.Namespace}}
 	ctx := context.Background()
 
 	_, err = t.r.Reconcile(ctx, req)
@@ -147,6 +153,158 @@ func (t *TestUtil) CreateSlackChannelObject(name string, isPrivate bool, topic s
 	}
 }
 
+// CreateSlackWorkspace creates a SlackWorkspace and the secret containing its token
+func (t *TestUtil) CreateSlackWorkspace(name string, token string, namespace string) *slackv1alpha1.SlackWorkspace {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + "-token",
+			Namespace: namespace,
+		},
+		StringData: map[string]string{
+			"APIToken": token,
+		},
+	}
+
+	err := t.k8sClient.Create(t.ctx, secret)
+	if err != nil {
+		ginkgo.Fail(err.Error())
+	}
+
+	workspace := &slackv1alpha1.SlackWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: slackv1alpha1.SlackWorkspaceSpec{
+			TokenSecretRef: slackv1alpha1.SecretKeyReference{
+				Name: secret.Name,
+			},
+		},
+	}
+
+	err = t.k8sClient.Create(t.ctx, workspace)
+	if err != nil {
+		ginkgo.Fail(err.Error())
+	}
+
+	return workspace
+}
+
+// GetSlackWorkspace fetches a SlackWorkspace object from kubernetes
+func (t *TestUtil) GetSlackWorkspace(name string, namespace string) *slackv1alpha1.SlackWorkspace {
+	workspace := &slackv1alpha1.SlackWorkspace{}
+	err := t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, workspace)
+
+	if err != nil {
+		ginkgo.Fail(err.Error())
+	}
+
+	return workspace
+}
+
+// TryDeleteSlackWorkspace - Tries to delete the SlackWorkspace and its token secret, does not fail on any error
+func (t *TestUtil) TryDeleteSlackWorkspace(name string, namespace string) {
+	objectMeta := metav1.ObjectMeta{Name: name, Namespace: namespace}
+	_ = t.k8sClient.Delete(t.ctx, &slackv1alpha1.SlackWorkspace{ObjectMeta: objectMeta})
+
+	objectMeta.Name = name + "-token"
+	_ = t.k8sClient.Delete(t.ctx, &corev1.Secret{ObjectMeta: objectMeta})
+}
+
+// CreateUserGroup creates a UserGroup object in kubernetes
+func (t *TestUtil) CreateUserGroup(name string, handle string, users []string, channels []string, namespace string) *slackv1alpha1.UserGroup {
+	userGroup := &slackv1alpha1.UserGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: slackv1alpha1.UserGroupSpec{
+			Handle:   handle,
+			Name:     name,
+			Users:    users,
+			Channels: channels,
+		},
+	}
+
+	err := t.k8sClient.Create(t.ctx, userGroup)
+	if err != nil {
+		ginkgo.Fail(err.Error())
+	}
+
+	return userGroup
+}
+
+// GetUserGroup fetches a UserGroup object from kubernetes
+func (t *TestUtil) GetUserGroup(name string, namespace string) *slackv1alpha1.UserGroup {
+	userGroup := &slackv1alpha1.UserGroup{}
+	err := t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, userGroup)
+
+	if err != nil {
+		ginkgo.Fail(err.Error())
+	}
+
+	return userGroup
+}
+
+// TryDeleteUserGroup - Tries to delete the UserGroup without finalizing it, does not fail on any error
+func (t *TestUtil) TryDeleteUserGroup(name string, namespace string) {
+	userGroup := &slackv1alpha1.UserGroup{}
+	err := t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, userGroup)
+	if err != nil {
+		return
+	}
+
+	userGroup.Finalizers = []string{}
+	_ = t.k8sClient.Update(t.ctx, userGroup)
+	_ = t.k8sClient.Delete(t.ctx, userGroup)
+}
+
+// CreateMessage creates a Message object in kubernetes
+func (t *TestUtil) CreateMessage(name string, channel string, text string, namespace string) *slackv1alpha1.Message {
+	message := &slackv1alpha1.Message{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: slackv1alpha1.MessageSpec{
+			Channel: channel,
+			Text:    text,
+		},
+	}
+
+	err := t.k8sClient.Create(t.ctx, message)
+	if err != nil {
+		ginkgo.Fail(err.Error())
+	}
+
+	return message
+}
+
+// GetMessage fetches a Message object from kubernetes
+func (t *TestUtil) GetMessage(name string, namespace string) *slackv1alpha1.Message {
+	message := &slackv1alpha1.Message{}
+	err := t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, message)
+
+	if err != nil {
+		ginkgo.Fail(err.Error())
+	}
+
+	return message
+}
+
+// TryDeleteMessage - Tries to delete the Message without finalizing it, does not fail on any error
+func (t *TestUtil) TryDeleteMessage(name string, namespace string) {
+	message := &slackv1alpha1.Message{}
+	err := t.k8sClient.Get(t.ctx, types.NamespacedName{Name: name, Namespace: namespace}, message)
+	if err != nil {
+		return
+	}
+
+	message.Finalizers = []string{}
+	_ = t.k8sClient.Update(t.ctx, message)
+	_ = t.k8sClient.Delete(t.ctx, message)
+}
+
 // RandSeq Generates a letter sequence with `n` characters
 func (t *TestUtil) RandSeq(n int) string {
 	letters := []rune("abcdefghijklmnopqrstuvwxyz")
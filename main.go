@@ -35,7 +35,9 @@ import (
 
 	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
 	"github.com/stakater/slack-operator/controllers"
+	"github.com/stakater/slack-operator/pkg/alertmanager"
 	config "github.com/stakater/slack-operator/pkg/config"
+	"github.com/stakater/slack-operator/pkg/events"
 	slack "github.com/stakater/slack-operator/pkg/slack"
 	// +kubebuilder:scaffold:imports
 )
@@ -56,9 +58,13 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var slackEventsAddr string
+	var alertmanagerAddr string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&slackEventsAddr, "slack-events-bind-address", "0", "The address the slack events endpoint binds to. Set to 0 to disable it.")
+	flag.StringVar(&alertmanagerAddr, "alertmanager-bind-address", "0", "The address the alertmanager webhook endpoint binds to. Set to 0 to disable it.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -71,6 +77,13 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	operatorConfig, err := config.GetOperatorConfig()
+	if err != nil {
+		setupLog.Error(err, "unable to read operator config")
+		os.Exit(1)
+	}
+	config.Set(operatorConfig)
+
 	watchNamespace, err := getWatchNamespace()
 	if err != nil {
 		setupLog.Info("Unable to fetch WatchNamespace, the manager will watch and manage resources in all Namespaces")
@@ -101,17 +114,122 @@ func main() {
 		os.Exit(1)
 	}
 
-	slackAPIToken := config.ReadSlackTokenSecret(mgr.GetAPIReader())
+	operatorNamespace, err := config.GetOperatorNamespace()
+	if err != nil {
+		setupLog.Error(err, "unable to get operator namespace")
+		os.Exit(1)
+	}
 
-	if err = (&controllers.ChannelReconciler{
+	// The service is degraded until the token reconciler loads the token from the secret
+	slackService := slack.New("", ctrl.Log.WithName("service").WithName("Slack"))
+	workspaces := slack.NewServiceCache(func(token string) slack.Service {
+		return slack.New(token, ctrl.Log.WithName("service").WithName("Slack"))
+	})
+
+	tokenReconciler := &controllers.SlackTokenReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("SlackToken"),
+		Namespace:    operatorNamespace,
+		SlackService: slackService,
+	}
+
+	channelReconciler := &controllers.ChannelReconciler{
 		Client:       mgr.GetClient(),
 		Log:          ctrl.Log.WithName("controllers").WithName("Channel"),
 		Scheme:       mgr.GetScheme(),
-		SlackService: slack.New(slackAPIToken, ctrl.Log.WithName("service").WithName("Slack")),
-	}).SetupWithManager(mgr); err != nil {
+		SlackService: slackService,
+		APIReader:    mgr.GetAPIReader(),
+		Workspaces:   workspaces,
+		TokenEvents:  tokenReconciler.Events(),
+	}
+
+	if slackEventsAddr != "0" {
+		receiver := events.NewReceiver(slackEventsAddr, "", mgr.GetClient(), ctrl.Log.WithName("events").WithName("Slack"))
+		if err = receiver.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up slack events receiver")
+			os.Exit(1)
+		}
+		receiver.OnUserChange(func(teamID string) {
+			slackService.InvalidateUsers(teamID)
+			workspaces.InvalidateUsers(teamID)
+		})
+		tokenReconciler.OnSigningSecret = receiver.SetSigningSecret
+		channelReconciler.SlackEvents = receiver.Events()
+	}
+
+	if alertmanagerAddr != "0" {
+		receiver := alertmanager.NewReceiver(alertmanagerAddr, mgr.GetClient(), channelReconciler.SlackServiceFor, ctrl.Log.WithName("alertmanager"))
+		if err = mgr.Add(receiver); err != nil {
+			setupLog.Error(err, "unable to set up alertmanager receiver")
+			os.Exit(1)
+		}
+		tokenReconciler.OnAlertmanagerCredentials = receiver.SetCredentials
+	}
+
+	if err = tokenReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SlackToken")
+		os.Exit(1)
+	}
+	if err = channelReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Channel")
 		os.Exit(1)
 	}
+	if err = (&controllers.SlackWorkspaceReconciler{
+		Client:     mgr.GetClient(),
+		Log:        ctrl.Log.WithName("controllers").WithName("SlackWorkspace"),
+		Scheme:     mgr.GetScheme(),
+		APIReader:  mgr.GetAPIReader(),
+		Workspaces: workspaces,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SlackWorkspace")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.UserGroupReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("UserGroup"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "UserGroup")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.MessageReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("Message"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+		APIReader:    mgr.GetAPIReader(),
+		Workspaces:   workspaces,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Message")
+		os.Exit(1)
+	}
+
+	// Watching the Events, Pods and Deployments of the cluster is costly, so notifications are opt-in
+	if operatorConfig.Features.Notifications {
+		if err = (&controllers.NotificationReconciler{
+			Client:       mgr.GetClient(),
+			Log:          ctrl.Log.WithName("controllers").WithName("Notification"),
+			SlackService: slackService,
+			APIReader:    mgr.GetAPIReader(),
+			Namespace:    operatorNamespace,
+			Workspaces:   workspaces,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Notification")
+			os.Exit(1)
+		}
+	}
+
+	// Channels pick up the reloaded config on their next reconcile, the token is reloaded in case its secret changed
+	err = mgr.Add(config.NewWatcher(config.GetConfigFilePath(), config.DefaultReloadInterval, func(*config.Config) {
+		tokenReconciler.Reload()
+	}))
+	if err != nil {
+		setupLog.Error(err, "unable to set up config watcher")
+		os.Exit(1)
+	}
 
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = (&slackv1alpha1.Channel{}).SetupWebhookWithManager(mgr); err != nil {
@@ -125,10 +243,20 @@ func main() {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+	if err := mgr.AddReadyzCheck("ping", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	// The readiness probe of the pod only uses /readyz/ping, failing it without a token would take the webhooks out of
+	// the service and reject every change to Channels, including finalizer removals
+	if err := mgr.AddReadyzCheck("slack-token", slackService.CheckToken); err != nil {
+		setupLog.Error(err, "unable to set up slack token check")
+		os.Exit(1)
+	}
+	if err := slack.RegisterTokenMetric(slackService); err != nil {
+		setupLog.Error(err, "unable to set up slack token metric")
+		os.Exit(1)
+	}
 
 	// +kubebuilder:scaffold:builder
 
@@ -0,0 +1,351 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/config"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+const (
+	// Path on which the Alertmanager webhooks are received
+	Path string = "/alertmanager"
+
+	statusFiring   string = "firing"
+	statusResolved string = "resolved"
+
+	// threadTTL is the time after which the message of an alert that never resolved, or the resolution of an alert, is
+	// forgotten
+	threadTTL time.Duration = 7 * 24 * time.Hour
+)
+
+// Message is the payload of an Alertmanager webhook
+type Message struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []Alert           `json:"alerts"`
+}
+
+// Alert is an alert of an Alertmanager webhook, which the alert template is rendered with
+type Alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// Credentials that Alertmanager has to send with its webhooks, a bearer token or basic auth credentials, set in the
+// http_config of its webhook receiver
+type Credentials struct {
+	BearerToken string
+	Username    string
+	Password    string
+}
+
+// empty returns true if neither a bearer token nor basic auth credentials are set
+func (c Credentials) empty() bool {
+	return c.BearerToken == "" && (c.Username == "" || c.Password == "")
+}
+
+// authorizes returns true if the request sends the bearer token or the basic auth credentials
+func (c Credentials) authorizes(req *http.Request) bool {
+	if c.BearerToken != "" {
+		authorization := req.Header.Get("Authorization")
+		if strings.HasPrefix(authorization, "Bearer ") && equal(strings.TrimPrefix(authorization, "Bearer "), c.BearerToken) {
+			return true
+		}
+	}
+
+	if c.Username != "" && c.Password != "" {
+		username, password, ok := req.BasicAuth()
+		if ok && equal(username, c.Username) && equal(password, c.Password) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compares the secrets in constant time
+func equal(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// thread is the message posted for a firing alert, under which its resolution is posted
+type thread struct {
+	timestamp string
+	startsAt  time.Time
+
+	// resolved is true once the resolution of the alert that ended at resolvedAt was posted, Alertmanager sends
+	// resolved alerts again with the later notifications of their group and when retrying a webhook
+	resolved   bool
+	resolvedAt time.Time
+
+	// postedAt is the time the thread was last changed, it is forgotten threadTTL later
+	postedAt time.Time
+}
+
+// Receiver receives Alertmanager webhooks and posts the alerts in the slack channels of the Channels named by their
+// labels. The resolution of an alert is posted in the thread of the message posted when it fired. The messages of
+// firing alerts are only kept in memory, so alerts that resolve after a restart are posted as new messages.
+type Receiver struct {
+	log  logr.Logger
+	addr string
+
+	// credentialsMutex guards credentials, which change when the secret containing them is updated
+	credentialsMutex sync.RWMutex
+	credentials      Credentials
+
+	// findChannel returns the Channel with the namespace and name, or nil if it doesn't exist
+	findChannel func(ctx context.Context, namespace string, name string) (*slackv1alpha1.Channel, error)
+
+	// slackServiceFor returns the slack Service of the workspace the Channel belongs to
+	slackServiceFor func(ctx context.Context, channel *slackv1alpha1.Channel) (slack.Service, error)
+
+	// threadsMutex guards threads, which holds the messages of the firing alerts by slack channel and alert
+	threadsMutex sync.Mutex
+	threads      map[string]thread
+}
+
+// NewReceiver creates a new Receiver which listens on the given address. Webhooks are rejected until credentials are
+// set with SetCredentials.
+func NewReceiver(addr string, k8sReader client.Reader,
+	slackServiceFor func(context.Context, *slackv1alpha1.Channel) (slack.Service, error), logger logr.Logger) *Receiver {
+	return &Receiver{
+		log:             logger,
+		addr:            addr,
+		slackServiceFor: slackServiceFor,
+		threads:         map[string]thread{},
+		findChannel: func(ctx context.Context, namespace string, name string) (*slackv1alpha1.Channel, error) {
+			channel := &slackv1alpha1.Channel{}
+			err := k8sReader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, channel)
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return channel, nil
+		},
+	}
+}
+
+// SetCredentials sets the credentials that Alertmanager has to send with its webhooks
+func (r *Receiver) SetCredentials(credentials Credentials) {
+	r.credentialsMutex.Lock()
+	defer r.credentialsMutex.Unlock()
+
+	r.credentials = credentials
+}
+
+func (r *Receiver) getCredentials() Credentials {
+	r.credentialsMutex.RLock()
+	defer r.credentialsMutex.RUnlock()
+
+	return r.credentials
+}
+
+// Start serves the Alertmanager webhooks until the context is done
+func (r *Receiver) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path, r)
+
+	server := &http.Server{Addr: r.addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	r.log.Info("Starting alertmanager receiver", "addr", r.addr, "path", Path)
+	err := server.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	credentials := r.getCredentials()
+	if credentials.empty() {
+		r.log.Info("Rejecting alerts, the credentials have not been loaded yet")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if !credentials.authorizes(req) {
+		r.log.Info("Rejecting alerts without valid credentials")
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	message := Message{}
+	err := json.NewDecoder(req.Body).Decode(&message)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Alertmanager retries the whole webhook if it fails, alerts that were posted are not posted again
+	failed := false
+	for _, alert := range message.Alerts {
+		err := r.handleAlert(req.Context(), alert)
+		if err != nil {
+			r.log.Error(err, "Error posting alert", "alertname", alert.Labels["alertname"], "fingerprint", alert.Fingerprint)
+			failed = true
+		}
+	}
+
+	if failed {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleAlert posts the alert in the slack channel of the Channel named by its labels. Alerts that don't name a
+// Channel with a slack channel are dropped, as retrying them would not help.
+func (r *Receiver) handleAlert(ctx context.Context, alert Alert) error {
+	alertmanagerConfig := config.Get().Alertmanager
+	namespace := alert.Labels[alertmanagerConfig.NamespaceLabel]
+	name := alert.Labels[alertmanagerConfig.ChannelLabel]
+	log := r.log.WithValues("alertname", alert.Labels["alertname"], "channel", namespace+"/"+name)
+
+	if namespace == "" || name == "" {
+		log.Info("Dropping alert without channel labels", "namespaceLabel", alertmanagerConfig.NamespaceLabel, "channelLabel", alertmanagerConfig.ChannelLabel)
+		return nil
+	}
+
+	channel, err := r.findChannel(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+	if channel == nil || channel.Status.ID == "" {
+		log.Info("Dropping alert for a channel that doesn't exist")
+		return nil
+	}
+
+	slackService, err := r.slackServiceFor(ctx, channel)
+	if err != nil {
+		return err
+	}
+
+	text, err := renderAlert(alertmanagerConfig.Template, alert)
+	if err != nil {
+		return err
+	}
+
+	key := channel.Status.ID + "/" + fingerprintOf(alert)
+	firingThread, threaded := r.getThread(key)
+
+	switch alert.Status {
+	case statusFiring:
+		// Alertmanager repeats the notifications of alerts that keep firing
+		if threaded && firingThread.startsAt.Equal(alert.StartsAt) {
+			return nil
+		}
+
+		timestamp, err := slackService.PostMessage(channel.Status.ID, text, "")
+		if err != nil {
+			return err
+		}
+		log.V(1).Info("Posted firing alert", "timestamp", timestamp)
+		r.setThread(key, thread{timestamp: timestamp, startsAt: alert.StartsAt})
+	case statusResolved:
+		if threaded && firingThread.resolved && firingThread.resolvedAt.Equal(alert.EndsAt) {
+			return nil
+		}
+
+		if threaded && !firingThread.resolved {
+			_, err = slackService.ReplyToMessage(channel.Status.ID, firingThread.timestamp, text, "")
+		} else {
+			_, err = slackService.PostMessage(channel.Status.ID, text, "")
+		}
+		if err != nil {
+			return err
+		}
+		log.V(1).Info("Posted resolved alert", "threadTimestamp", firingThread.timestamp)
+		r.setThread(key, thread{timestamp: firingThread.timestamp, startsAt: alert.StartsAt, resolved: true, resolvedAt: alert.EndsAt})
+	default:
+		log.Info("Dropping alert with unknown status", "status", alert.Status)
+	}
+	return nil
+}
+
+func (r *Receiver) getThread(key string) (thread, bool) {
+	r.threadsMutex.Lock()
+	defer r.threadsMutex.Unlock()
+
+	firingThread, ok := r.threads[key]
+	return firingThread, ok
+}
+
+// setThread records the message or the resolution of an alert and forgets the ones that were posted too long ago
+func (r *Receiver) setThread(key string, firingThread thread) {
+	r.threadsMutex.Lock()
+	defer r.threadsMutex.Unlock()
+
+	for existingKey, existingThread := range r.threads {
+		if time.Since(existingThread.postedAt) > threadTTL {
+			delete(r.threads, existingKey)
+		}
+	}
+	firingThread.postedAt = time.Now()
+	r.threads[key] = firingThread
+}
+
+// renderAlert renders the alert with the template, missing labels and annotations are rendered as empty strings
+func renderAlert(text string, alert Alert) (string, error) {
+	var rendered bytes.Buffer
+
+	tmpl, err := template.New("alert").Option("missingkey=zero").Parse(text)
+	if err == nil {
+		err = tmpl.Execute(&rendered, alert)
+	}
+	if err != nil {
+		return "", fmt.Errorf("Error rendering alert: %s", err.Error())
+	}
+	return rendered.String(), nil
+}
+
+// fingerprintOf returns the fingerprint of the alert, or its sorted labels if Alertmanager didn't send one
+func fingerprintOf(alert Alert) string {
+	if alert.Fingerprint != "" {
+		return alert.Fingerprint
+	}
+
+	var labels []string
+	for name, value := range alert.Labels {
+		labels = append(labels, name+"="+value)
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
@@ -0,0 +1,186 @@
+package alertmanager
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/config"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	"github.com/stakater/slack-operator/pkg/slack/mock"
+)
+
+var log = zap.New()
+
+const bearerToken = "s3cr3t"
+
+func newTestReceiver() *Receiver {
+	slackService := slack.NewMockService(log)
+	receiver := NewReceiver("0", nil, func(ctx context.Context, channel *slackv1alpha1.Channel) (slack.Service, error) {
+		return slackService, nil
+	}, log)
+	receiver.SetCredentials(Credentials{BearerToken: bearerToken})
+	receiver.findChannel = func(ctx context.Context, namespace string, name string) (*slackv1alpha1.Channel, error) {
+		if namespace != "team-a" || name != "alerts" {
+			return nil, nil
+		}
+		return &slackv1alpha1.Channel{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     slackv1alpha1.ChannelStatus{ID: mock.PublicConversationID},
+		}, nil
+	}
+	return receiver
+}
+
+func postAlerts(t *testing.T, receiver *Receiver, status string, channelName string) *http.Response {
+	payload := `{
+		"version": "4",
+		"status": "` + status + `",
+		"alerts": [
+			{
+				"status": "` + status + `",
+				"labels": {"alertname": "KubePodCrashLooping", "namespace": "team-a", "slack_channel": "` + channelName + `"},
+				"annotations": {"summary": "Pod is crash looping"},
+				"startsAt": "2021-06-01T10:00:00Z",
+				"fingerprint": "c0ffee"
+			}
+		]
+	}`
+
+	return post(t, receiver, payload, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	})
+}
+
+// post posts the payload to the receiver, after authenticate has set the credentials of the request
+func post(t *testing.T, receiver *Receiver, payload string, authenticate func(*http.Request)) *http.Response {
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+Path, strings.NewReader(payload))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	authenticate(req)
+
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return res
+}
+
+func TestReceiver_shouldPostFiringAlert_andRememberItsMessage(t *testing.T) {
+	receiver := newTestReceiver()
+
+	res := postAlerts(t, receiver, statusFiring, "alerts")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, mock.NewMessageTimestamp, receiver.threads[mock.PublicConversationID+"/c0ffee"].timestamp)
+}
+
+func TestReceiver_shouldReplyToFiringAlert_whenAlertIsResolved(t *testing.T) {
+	receiver := newTestReceiver()
+
+	postAlerts(t, receiver, statusFiring, "alerts")
+
+	mock.ResetCalls()
+	res := postAlerts(t, receiver, statusResolved, "alerts")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.True(t, receiver.threads[mock.PublicConversationID+"/c0ffee"].resolved)
+
+	replies := mock.Calls("chat.postMessage")
+	assert.Len(t, replies, 1)
+	assert.Equal(t, mock.NewMessageTimestamp, replies[0].Params.Get("thread_ts"))
+}
+
+func TestReceiver_shouldPostResolvedAlertOnce_whenItIsSentAgain(t *testing.T) {
+	receiver := newTestReceiver()
+
+	postAlerts(t, receiver, statusFiring, "alerts")
+
+	mock.ResetCalls()
+	res := postAlerts(t, receiver, statusResolved, "alerts")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	res = postAlerts(t, receiver, statusResolved, "alerts")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	assert.Len(t, mock.Calls("chat.postMessage"), 1)
+}
+
+func TestReceiver_shouldPostResolvedAlertOnce_whenItsMessageIsUnknown(t *testing.T) {
+	receiver := newTestReceiver()
+
+	mock.ResetCalls()
+	postAlerts(t, receiver, statusResolved, "alerts")
+	postAlerts(t, receiver, statusResolved, "alerts")
+
+	calls := mock.Calls("chat.postMessage")
+	assert.Len(t, calls, 1)
+	assert.Empty(t, calls[0].Params.Get("thread_ts"))
+}
+
+func TestReceiver_shouldDropAlert_whenChannelDoesNotExist(t *testing.T) {
+	receiver := newTestReceiver()
+
+	res := postAlerts(t, receiver, statusFiring, "missing")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Empty(t, receiver.threads)
+}
+
+func TestReceiver_shouldRejectInvalidPayload(t *testing.T) {
+	res := post(t, newTestReceiver(), "{", func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	})
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestReceiver_shouldRejectRequest_withoutValidCredentials(t *testing.T) {
+	receiver := newTestReceiver()
+
+	res := post(t, receiver, "{}", func(req *http.Request) {})
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	res = post(t, receiver, "{}", func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer wrong")
+	})
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestReceiver_shouldAcceptBasicAuth(t *testing.T) {
+	receiver := newTestReceiver()
+	receiver.SetCredentials(Credentials{Username: "alertmanager", Password: bearerToken})
+
+	res := post(t, receiver, "{}", func(req *http.Request) {
+		req.SetBasicAuth("alertmanager", bearerToken)
+	})
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res = post(t, receiver, "{}", func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	})
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestReceiver_shouldRejectRequest_whenCredentialsAreNotLoaded(t *testing.T) {
+	receiver := newTestReceiver()
+	receiver.SetCredentials(Credentials{})
+
+	res := post(t, receiver, "{}", func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	})
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+}
+
+func TestRenderAlert_shouldRenderDefaultTemplate(t *testing.T) {
+	text, err := renderAlert(config.DefaultAlertTemplate, Alert{
+		Status:      statusFiring,
+		Labels:      map[string]string{"alertname": "KubePodCrashLooping", "severity": "warning"},
+		Annotations: map[string]string{"summary": "Pod is crash looping"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ":fire: *Firing*: KubePodCrashLooping (warning)\nPod is crash looping", text)
+}
@@ -1,38 +1,69 @@
 package config
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
+	"regexp"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	util "github.com/stakater/operator-utils/util"
-	secretsUtil "github.com/stakater/operator-utils/util/secrets"
 	"gopkg.in/yaml.v2"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
 const (
-	ErrorRequeueTime = 15 * time.Minute
+	DefaultErrorRequeueInterval = 15 * time.Minute
+	DefaultResyncPeriod         = 10 * time.Minute
+	DefaultUserCacheTTL         = 15 * time.Minute
+
+	DefaultConfigFilePath string = "config/operator/default-config.yaml"
+
+	// DefaultAlertTemplate renders an alert with its name, severity and summary and links to its source
+	DefaultAlertTemplate string = `{{ if eq .Status "firing" }}:fire: *Firing*{{ else }}:white_check_mark: *Resolved*{{ end }}: ` +
+		`{{ .Labels.alertname }}{{ with .Labels.severity }} ({{ . }}){{ end }}` +
+		`{{ with .Annotations.summary }}` + "\n" + `{{ . }}{{ end }}` +
+		`{{ with .GeneratorURL }}` + "\n" + `<{{ . }}|Source>{{ end }}`
 
 	SlackDefaultSecretName string = "slack-secret"
 	SlackAPITokenSecretKey string = "APIToken"
+	SlackSigningSecretKey  string = "SigningSecret"
+
+	AlertmanagerBearerTokenKey string = "AlertmanagerBearerToken"
+	AlertmanagerUsernameKey    string = "AlertmanagerUsername"
+	AlertmanagerPasswordKey    string = "AlertmanagerPassword"
 )
 
 var (
-	setupLog               = ctrl.Log.WithName("setup")
-	SlackSecretName string = getConfigSecretName()
+	setupLog = ctrl.Log.WithName("setup")
+
+	// current holds the *Config in use, which is replaced when the config file changes
+	current atomic.Value
+
+	deletionPolicyRegex   = regexp.MustCompile("^(Archive|Retain|RenameThenArchive)$")
+	driftPolicyRegex      = regexp.MustCompile("^(Enforce|ReportOnly)$")
+	membershipPolicyRegex = regexp.MustCompile("^(Authoritative|Additive|Ignore)$")
+	archiveSuffixRegex    = regexp.MustCompile("^[a-z0-9_-]+$")
 )
 
 // Config struct for operator config yaml
 type Config struct {
-	Slack Slack `yaml:"slack"`
+	Slack           Slack           `yaml:"slack"`
+	Requeue         Requeue         `yaml:"requeue"`
+	ResyncPeriod    Duration        `yaml:"resyncPeriod"`
+	UserCacheTTL    Duration        `yaml:"userCacheTTL"`
+	ChannelDefaults ChannelDefaults `yaml:"channelDefaults"`
+	Features        Features        `yaml:"features"`
+	Alertmanager    Alertmanager    `yaml:"alertmanager"`
 }
 
 // Slack for config yaml structure
 type Slack struct {
-	APIToken APIToken `yaml:"APIToken"`
+	APIToken         APIToken `yaml:"APIToken"`
+	SigningSecretKey string   `yaml:"signingSecretKey"`
 }
 
 // APIToken for config yaml structure
@@ -41,10 +72,135 @@ type APIToken struct {
 	Key        string `yaml:"key"`
 }
 
+// Requeue for config yaml structure
+type Requeue struct {
+	// ErrorInterval is the interval after which a resource that failed to reconcile is retried
+	ErrorInterval Duration `yaml:"errorInterval"`
+}
+
+// ChannelDefaults for config yaml structure, written into the fields that a Channel leaves empty by the defaulting
+// webhook
+type ChannelDefaults struct {
+	DeletionPolicy   string `yaml:"deletionPolicy"`
+	ArchiveSuffix    string `yaml:"archiveSuffix"`
+	DriftPolicy      string `yaml:"driftPolicy"`
+	MembershipPolicy string `yaml:"membershipPolicy"`
+
+	// NameTemplate is the Go template over the Channel, e.g. "{{ .Namespace }}-{{ .Labels.team }}", from which the
+	// name of a Channel without one is derived
+	NameTemplate string `yaml:"nameTemplate"`
+}
+
+// Features for config yaml structure
+type Features struct {
+	// Adoption allows Channels to adopt existing slack channels
+	Adoption bool `yaml:"adoption"`
+
+	// SlackWorkspaces allows Channels to use the token of a SlackWorkspace
+	SlackWorkspaces bool `yaml:"slackWorkspaces"`
+
+	// Notifications watches the Events, Pods and Deployments of the cluster to post the notifications of Channels,
+	// only read on start
+	Notifications bool `yaml:"notifications"`
+}
+
+// Alertmanager for config yaml structure, used by the receiver of Alertmanager webhooks
+type Alertmanager struct {
+	// NamespaceLabel is the label of an alert holding the namespace of the Channel to post it in
+	NamespaceLabel string `yaml:"namespaceLabel"`
+
+	// ChannelLabel is the label of an alert holding the name of the Channel to post it in
+	ChannelLabel string `yaml:"channelLabel"`
+
+	// Template is the Go template of the messages posted for alerts
+	Template string `yaml:"template"`
+
+	// BearerTokenKey is the key of the secret containing the API token which holds the bearer token that Alertmanager
+	// has to send
+	BearerTokenKey string `yaml:"bearerTokenKey"`
+
+	// UsernameKey and PasswordKey are the keys of the secret containing the API token which hold the basic auth
+	// credentials that Alertmanager has to send
+	UsernameKey string `yaml:"usernameKey"`
+	PasswordKey string `yaml:"passwordKey"`
+}
+
+// Duration is a time.Duration written as a string like "10m" in the config yaml
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalYAML parses the duration from a string
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	err := unmarshal(&value)
+	if err != nil {
+		return err
+	}
+
+	d.Duration, err = time.ParseDuration(value)
+	return err
+}
+
 var log = zap.New()
 
+func init() {
+	config := Default()
+	err := applyEnvOverrides(config)
+	if err != nil {
+		setupLog.Error(err, "Ignoring invalid environment variables")
+		config = Default()
+	}
+	current.Store(config)
+}
+
+// Default returns the config used for the settings missing in the config file
+func Default() *Config {
+	return &Config{
+		Slack: Slack{
+			APIToken: APIToken{
+				SecretName: SlackDefaultSecretName,
+				Key:        SlackAPITokenSecretKey,
+			},
+			SigningSecretKey: SlackSigningSecretKey,
+		},
+		Requeue: Requeue{
+			ErrorInterval: Duration{DefaultErrorRequeueInterval},
+		},
+		ResyncPeriod: Duration{DefaultResyncPeriod},
+		UserCacheTTL: Duration{DefaultUserCacheTTL},
+		ChannelDefaults: ChannelDefaults{
+			DeletionPolicy:   "Archive",
+			DriftPolicy:      "Enforce",
+			MembershipPolicy: "Authoritative",
+		},
+		Features: Features{
+			Adoption:        true,
+			SlackWorkspaces: true,
+		},
+		Alertmanager: Alertmanager{
+			NamespaceLabel: "namespace",
+			ChannelLabel:   "slack_channel",
+			Template:       DefaultAlertTemplate,
+			BearerTokenKey: AlertmanagerBearerTokenKey,
+			UsernameKey:    AlertmanagerUsernameKey,
+			PasswordKey:    AlertmanagerPasswordKey,
+		},
+	}
+}
+
+// Get returns the config in use
+func Get() *Config {
+	return current.Load().(*Config)
+}
+
+// Set replaces the config in use
+func Set(config *Config) {
+	current.Store(config)
+}
+
 func readConfig(filePath string) (*Config, error) {
-	var config Config
+	config := Default()
 
 	// Read YML
 	source, err := ioutil.ReadFile(filePath)
@@ -52,55 +208,140 @@ func readConfig(filePath string) (*Config, error) {
 		return nil, err
 	}
 
-	// Unmarshall
-	err = yaml.Unmarshal(source, &config)
+	// Unmarshall over the defaults, so that missing settings keep their default
+	err = yaml.UnmarshalStrict(source, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = applyEnvOverrides(config)
 	if err != nil {
 		return nil, err
 	}
 
-	return &config, nil
+	err = config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
 }
 
-// GetOperatorConfig returns the config object for the operator
-func GetOperatorConfig() (*Config, error) {
+// GetConfigFilePath returns the path of the config file, set by CONFIG_FILE_PATH
+func GetConfigFilePath() string {
 	configFilePath := os.Getenv("CONFIG_FILE_PATH")
 	if len(configFilePath) == 0 {
-		configFilePath = "config/operator/default-config.yaml"
+		configFilePath = DefaultConfigFilePath
 	}
+	return configFilePath
+}
+
+// GetOperatorConfig returns the config object for the operator. The defaults are used if the config file doesn't
+// exist, environment variables override the settings of the config file.
+func GetOperatorConfig() (*Config, error) {
+	configFilePath := GetConfigFilePath()
 
 	log.Info("Reading config file", "configFilePath", configFilePath)
 	config, err := readConfig(configFilePath)
+	if os.IsNotExist(err) {
+		log.Info("Config file doesn't exist, using default config", "configFilePath", configFilePath)
+		config = Default()
+		err = applyEnvOverrides(config)
+		if err == nil {
+			err = config.Validate()
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 	return config, nil
 }
 
-func getConfigSecretName() string {
-	configSecretName, _ := os.LookupEnv("CONFIG_SECRET_NAME")
-	if len(configSecretName) == 0 {
-		configSecretName = SlackDefaultSecretName
-		setupLog.Info("CONFIG_SECRET_NAME is unset, using default value: " + SlackDefaultSecretName)
+// applyEnvOverrides overrides the settings of the config with the environment variables that are set
+func applyEnvOverrides(config *Config) error {
+	if secretName, ok := os.LookupEnv("CONFIG_SECRET_NAME"); ok && len(secretName) > 0 {
+		config.Slack.APIToken.SecretName = secretName
 	}
-	return configSecretName
-}
 
-func ReadSlackTokenSecret(k8sReader client.Reader) string {
-	operatorNamespace, _ := os.LookupEnv("OPERATOR_NAMESPACE")
-	if len(operatorNamespace) == 0 {
-		operatorNamespaceTemp, err := util.GetOperatorNamespace()
+	durations := map[string]*Duration{
+		"ERROR_REQUEUE_INTERVAL": &config.Requeue.ErrorInterval,
+		"RESYNC_PERIOD":          &config.ResyncPeriod,
+		"USER_CACHE_TTL":         &config.UserCacheTTL,
+	}
+	for envVar, duration := range durations {
+		value, ok := os.LookupEnv(envVar)
+		if !ok || len(value) == 0 {
+			continue
+		}
+
+		parsed, err := time.ParseDuration(value)
 		if err != nil {
-			setupLog.Error(err, "Unable to get operator namespace")
-			os.Exit(1)
+			return fmt.Errorf("invalid %s: %s", envVar, err.Error())
 		}
-		operatorNamespace = operatorNamespaceTemp
+		duration.Duration = parsed
 	}
 
-	token, err := secretsUtil.LoadSecretData(k8sReader, SlackSecretName, operatorNamespace, SlackAPITokenSecretKey)
-	if err != nil {
-		setupLog.Error(err, "Could not read API token from key", "secretName", SlackSecretName, "secretKey", SlackAPITokenSecretKey)
-		os.Exit(1)
+	return nil
+}
+
+// Validate checks that the settings of the config are valid
+func (config *Config) Validate() error {
+	if config.Slack.APIToken.SecretName == "" {
+		return fmt.Errorf("slack.APIToken.secretName is required")
+	}
+	if config.Slack.APIToken.Key == "" {
+		return fmt.Errorf("slack.APIToken.key is required")
+	}
+	if config.Slack.SigningSecretKey == "" {
+		return fmt.Errorf("slack.signingSecretKey is required")
+	}
+
+	if config.Requeue.ErrorInterval.Duration <= 0 {
+		return fmt.Errorf("requeue.errorInterval must be positive")
+	}
+	if config.ResyncPeriod.Duration < 0 {
+		return fmt.Errorf("resyncPeriod can not be negative")
+	}
+	if config.UserCacheTTL.Duration < 0 {
+		return fmt.Errorf("userCacheTTL can not be negative")
 	}
 
-	return token
+	defaults := config.ChannelDefaults
+	if !deletionPolicyRegex.MatchString(defaults.DeletionPolicy) {
+		return fmt.Errorf("channelDefaults.deletionPolicy must be one of Archive, Retain or RenameThenArchive")
+	}
+	if !driftPolicyRegex.MatchString(defaults.DriftPolicy) {
+		return fmt.Errorf("channelDefaults.driftPolicy must be one of Enforce or ReportOnly")
+	}
+	if !membershipPolicyRegex.MatchString(defaults.MembershipPolicy) {
+		return fmt.Errorf("channelDefaults.membershipPolicy must be one of Authoritative, Additive or Ignore")
+	}
+	if defaults.DeletionPolicy == "RenameThenArchive" && !archiveSuffixRegex.MatchString(defaults.ArchiveSuffix) {
+		return fmt.Errorf("channelDefaults.archiveSuffix is required with the RenameThenArchive deletion policy and can only contain lowercase letters, numbers, hyphens and underscores")
+	}
+
+	if _, err := template.New("name").Parse(defaults.NameTemplate); err != nil {
+		return fmt.Errorf("channelDefaults.nameTemplate is invalid: %s", err.Error())
+	}
+
+	if config.Alertmanager.NamespaceLabel == "" || config.Alertmanager.ChannelLabel == "" {
+		return fmt.Errorf("alertmanager.namespaceLabel and alertmanager.channelLabel are required")
+	}
+	if _, err := template.New("alert").Parse(config.Alertmanager.Template); err != nil {
+		return fmt.Errorf("alertmanager.template is invalid: %s", err.Error())
+	}
+	if config.Alertmanager.BearerTokenKey == "" || config.Alertmanager.UsernameKey == "" || config.Alertmanager.PasswordKey == "" {
+		return fmt.Errorf("alertmanager.bearerTokenKey, alertmanager.usernameKey and alertmanager.passwordKey are required")
+	}
+
+	return nil
+}
+
+// GetOperatorNamespace returns the namespace of the operator, which contains the slack secret
+func GetOperatorNamespace() (string, error) {
+	operatorNamespace, _ := os.LookupEnv("OPERATOR_NAMESPACE")
+	if len(operatorNamespace) == 0 {
+		return util.GetOperatorNamespace()
+	}
+	return operatorNamespace, nil
 }
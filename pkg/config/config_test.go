@@ -0,0 +1,150 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	err := ioutil.WriteFile(filePath, []byte(content), 0600)
+	assert.NoError(t, err)
+	return filePath
+}
+
+func useConfigFile(t *testing.T, filePath string) {
+	os.Setenv("CONFIG_FILE_PATH", filePath)
+	t.Cleanup(func() { os.Unsetenv("CONFIG_FILE_PATH") })
+}
+
+func TestGetOperatorConfig_shouldUseDefaults_whenFileDoesNotExist(t *testing.T) {
+	useConfigFile(t, filepath.Join(t.TempDir(), "missing.yaml"))
+
+	config, err := GetOperatorConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, Default(), config)
+}
+
+func TestGetOperatorConfig_shouldKeepDefaults_forMissingSettings(t *testing.T) {
+	useConfigFile(t, writeConfigFile(t, `
+resyncPeriod: 1h
+channelDefaults:
+  deletionPolicy: RenameThenArchive
+  archiveSuffix: -archived
+features:
+  adoption: false
+`))
+
+	config, err := GetOperatorConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, config.ResyncPeriod.Duration)
+	assert.Equal(t, DefaultErrorRequeueInterval, config.Requeue.ErrorInterval.Duration)
+	assert.Equal(t, SlackDefaultSecretName, config.Slack.APIToken.SecretName)
+	assert.Equal(t, "RenameThenArchive", config.ChannelDefaults.DeletionPolicy)
+	assert.Equal(t, "Enforce", config.ChannelDefaults.DriftPolicy)
+	assert.Equal(t, "Authoritative", config.ChannelDefaults.MembershipPolicy)
+	assert.False(t, config.Features.Adoption)
+	assert.True(t, config.Features.SlackWorkspaces)
+}
+
+func TestGetOperatorConfig_shouldApplyEnvOverrides(t *testing.T) {
+	useConfigFile(t, writeConfigFile(t, "resyncPeriod: 1h\n"))
+	os.Setenv("RESYNC_PERIOD", "5m")
+	os.Setenv("CONFIG_SECRET_NAME", "other-slack-secret")
+	defer os.Unsetenv("RESYNC_PERIOD")
+	defer os.Unsetenv("CONFIG_SECRET_NAME")
+
+	config, err := GetOperatorConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, config.ResyncPeriod.Duration)
+	assert.Equal(t, "other-slack-secret", config.Slack.APIToken.SecretName)
+}
+
+func TestGetOperatorConfig_shouldFail_whenConfigIsInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown setting":       "resyncPeriods: 1h\n",
+		"invalid duration":      "resyncPeriod: soon\n",
+		"zero error interval":   "requeue:\n  errorInterval: 0s\n",
+		"unknown policy":        "channelDefaults:\n  driftPolicy: Ignore\n",
+		"unknown membership":    "channelDefaults:\n  membershipPolicy: Enforce\n",
+		"missing archiveSuffix": "channelDefaults:\n  deletionPolicy: RenameThenArchive\n",
+		"empty secret name":     "slack:\n  APIToken:\n    secretName: \"\"\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			useConfigFile(t, writeConfigFile(t, content))
+
+			_, err := GetOperatorConfig()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWatcher_shouldSetConfig_whenFileChanged(t *testing.T) {
+	filePath := writeConfigFile(t, "resyncPeriod: 1h\n")
+	defer Set(Get())
+
+	var reloaded *Config
+	watcher := NewWatcher(filePath, DefaultReloadInterval, func(config *Config) { reloaded = config })
+
+	watcher.reload()
+	assert.Nil(t, reloaded, "unchanged file should not be reloaded")
+
+	err := ioutil.WriteFile(filePath, []byte("resyncPeriod: 2h\n"), 0600)
+	assert.NoError(t, err)
+
+	watcher.reload()
+	assert.NotNil(t, reloaded)
+	assert.Same(t, reloaded, Get())
+	assert.Equal(t, 2*time.Hour, Get().ResyncPeriod.Duration)
+}
+
+func TestWatcher_shouldKeepConfig_whenFileIsInvalid(t *testing.T) {
+	filePath := writeConfigFile(t, "resyncPeriod: 1h\n")
+	current := Get()
+	defer Set(current)
+
+	watcher := NewWatcher(filePath, DefaultReloadInterval, func(*Config) {
+		t.Error("invalid config should not be reloaded")
+	})
+
+	err := ioutil.WriteFile(filePath, []byte("resyncPeriod: -1h\n"), 0600)
+	assert.NoError(t, err)
+
+	watcher.reload()
+	assert.Same(t, current, Get())
+}
+
+func TestGetOperatorConfig_shouldFail_whenAlertTemplateIsInvalid(t *testing.T) {
+	useConfigFile(t, writeConfigFile(t, `
+alertmanager:
+  template: "{{ .Labels.alertname"
+`))
+
+	_, err := GetOperatorConfig()
+	assert.Error(t, err)
+}
+
+func TestGetOperatorConfig_shouldFail_whenAlertmanagerCredentialKeyIsEmpty(t *testing.T) {
+	useConfigFile(t, writeConfigFile(t, `
+alertmanager:
+  bearerTokenKey: ""
+`))
+
+	_, err := GetOperatorConfig()
+	assert.Error(t, err)
+}
+
+func TestGetOperatorConfig_shouldFail_whenNameTemplateIsInvalid(t *testing.T) {
+	useConfigFile(t, writeConfigFile(t, `
+channelDefaults:
+  nameTemplate: "{{ .Namespace }"
+`))
+
+	_, err := GetOperatorConfig()
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"time"
+)
+
+// DefaultReloadInterval is the interval at which the config file is checked for changes
+const DefaultReloadInterval = 10 * time.Second
+
+// Watcher reloads the config when the config file changes. The file is polled rather than watched, so that the
+// symlink swaps of a mounted ConfigMap are picked up as well.
+type Watcher struct {
+	filePath string
+	interval time.Duration
+	onChange func(*Config)
+
+	source []byte
+}
+
+// NewWatcher creates a Watcher for the config file, onChange is called after a valid config was loaded
+func NewWatcher(filePath string, interval time.Duration, onChange func(*Config)) *Watcher {
+	source, _ := ioutil.ReadFile(filePath)
+
+	return &Watcher{
+		filePath: filePath,
+		interval: interval,
+		onChange: onChange,
+		source:   source,
+	}
+}
+
+// Start polls the config file until the context is done
+func (w *Watcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.reload()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection returns false, every replica reloads its config
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+func (w *Watcher) reload() {
+	source, err := ioutil.ReadFile(w.filePath)
+	if err != nil || bytes.Equal(source, w.source) {
+		return
+	}
+	w.source = source
+
+	config, err := readConfig(w.filePath)
+	if err != nil {
+		log.Error(err, "Ignoring invalid config file, keeping the current config", "configFilePath", w.filePath)
+		return
+	}
+
+	log.Info("Reloaded config file", "configFilePath", w.filePath)
+	Set(config)
+	if w.onChange != nil {
+		w.onChange(config)
+	}
+}
@@ -0,0 +1,264 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+const (
+	// ChannelIDField is the field index used to look up Channels by the ID of their slack channel
+	ChannelIDField string = "status.id"
+
+	// Path on which the slack events are received
+	Path string = "/slack/events"
+
+	eventBufferSize int = 100
+)
+
+// channelEventTypes are the slack events that change a channel out of band and require its Channel to be reconciled
+var channelEventTypes = map[string]bool{
+	"channel_rename":        true,
+	"channel_archive":       true,
+	"channel_unarchive":     true,
+	"channel_deleted":       true,
+	"group_rename":          true,
+	"group_archive":         true,
+	"group_unarchive":       true,
+	"group_deleted":         true,
+	"member_joined_channel": true,
+	"member_left_channel":   true,
+}
+
+// userEventTypes are the slack events that add or change users of the workspace
+var userEventTypes = map[string]bool{
+	"team_join":   true,
+	"user_change": true,
+}
+
+// outerEvent is the envelope in which the slack Events API posts events
+type outerEvent struct {
+	Type      string          `json:"type"`
+	Challenge string          `json:"challenge"`
+	TeamID    string          `json:"team_id"`
+	Event     json.RawMessage `json:"event"`
+}
+
+// innerEvent contains the fields of the slack events that identify the affected channel
+type innerEvent struct {
+	Type    string          `json:"type"`
+	Channel json.RawMessage `json:"channel"`
+}
+
+// Receiver receives events from the slack Events API and enqueues the Channels of the affected slack channels
+type Receiver struct {
+	log    logr.Logger
+	addr   string
+	events chan event.GenericEvent
+
+	// signingSecretMutex guards signingSecret, which changes when the secret containing it is updated
+	signingSecretMutex sync.RWMutex
+	signingSecret      string
+
+	// findChannels returns the Channels that manage the slack channel with the given ID
+	findChannels func(ctx context.Context, channelID string) ([]slackv1alpha1.Channel, error)
+
+	// onUserChange is called with the ID of the workspace whose users were added or changed
+	onUserChange func(teamID string)
+}
+
+// NewReceiver creates a new Receiver which listens on the given address. Events are rejected while the signing
+// secret is empty
+func NewReceiver(addr string, signingSecret string, k8sReader client.Reader, logger logr.Logger) *Receiver {
+	return &Receiver{
+		log:           logger,
+		addr:          addr,
+		signingSecret: signingSecret,
+		events:        make(chan event.GenericEvent, eventBufferSize),
+		findChannels: func(ctx context.Context, channelID string) ([]slackv1alpha1.Channel, error) {
+			channelList := &slackv1alpha1.ChannelList{}
+			err := k8sReader.List(ctx, channelList, client.MatchingFields{ChannelIDField: channelID})
+			if err != nil {
+				return nil, err
+			}
+			return channelList.Items, nil
+		},
+	}
+}
+
+// Events returns the channel on which the Channels to reconcile are sent
+func (r *Receiver) Events() <-chan event.GenericEvent {
+	return r.events
+}
+
+// SetSigningSecret sets the signing secret used to verify the requests of the slack Events API
+func (r *Receiver) SetSigningSecret(signingSecret string) {
+	r.signingSecretMutex.Lock()
+	defer r.signingSecretMutex.Unlock()
+
+	r.signingSecret = signingSecret
+}
+
+func (r *Receiver) getSigningSecret() string {
+	r.signingSecretMutex.RLock()
+	defer r.signingSecretMutex.RUnlock()
+
+	return r.signingSecret
+}
+
+// OnUserChange registers a function which is called with the ID of the workspace whose users were added or changed
+func (r *Receiver) OnUserChange(f func(teamID string)) {
+	r.onUserChange = f
+}
+
+// SetupWithManager indexes Channels by the ID of their slack channel and adds the Receiver to the manager
+func (r *Receiver) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &slackv1alpha1.Channel{}, ChannelIDField, func(obj client.Object) []string {
+		channel := obj.(*slackv1alpha1.Channel)
+		if channel.Status.ID == "" {
+			return nil
+		}
+		return []string{channel.Status.ID}
+	})
+	if err != nil {
+		return err
+	}
+
+	return mgr.Add(r)
+}
+
+// Start serves the slack events until the context is done
+func (r *Receiver) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path, r)
+
+	server := &http.Server{Addr: r.addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	r.log.Info("Starting slack events receiver", "addr", r.addr, "path", Path)
+	err := server.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	signingSecret := r.getSigningSecret()
+	if signingSecret == "" {
+		r.log.Info("Rejecting slack event, the signing secret has not been loaded yet")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	verifier, err := slack.NewSecretsVerifier(req.Header, signingSecret)
+	if err != nil {
+		r.log.Info("Rejecting slack event without valid signature headers", "error", err.Error())
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	_, _ = verifier.Write(body)
+	if err = verifier.Ensure(); err != nil {
+		r.log.Info("Rejecting slack event with invalid signature", "error", err.Error())
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	outer := outerEvent{}
+	err = json.Unmarshal(body, &outer)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch outer.Type {
+	case slackevents.URLVerification:
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(outer.Challenge))
+	case slackevents.CallbackEvent:
+		inner := innerEvent{}
+		err = json.Unmarshal(outer.Event, &inner)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.handleEvent(req.Context(), outer.TeamID, inner)
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (r *Receiver) handleEvent(ctx context.Context, teamID string, inner innerEvent) {
+	if userEventTypes[inner.Type] {
+		r.log.V(1).Info("Users of the workspace changed", "type", inner.Type, "teamID", teamID)
+		if r.onUserChange != nil {
+			r.onUserChange(teamID)
+		}
+		return
+	}
+
+	if !channelEventTypes[inner.Type] {
+		return
+	}
+
+	channelID := parseChannelID(inner.Channel)
+	log := r.log.WithValues("type", inner.Type, "channelID", channelID)
+	if channelID == "" {
+		log.Info("Ignoring slack event without channel")
+		return
+	}
+
+	channels, err := r.findChannels(ctx, channelID)
+	if err != nil {
+		log.Error(err, "Error looking up Channels for slack event")
+		return
+	}
+
+	for i := range channels {
+		log.V(1).Info("Enqueueing Channel for slack event", "channel", channels[i].Namespace+"/"+channels[i].Name)
+		select {
+		case r.events <- event.GenericEvent{Object: &channels[i]}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// parseChannelID returns the channel ID from the channel field of an event, which is either the ID itself
+// or, e.g. for channel_rename, an object containing the ID
+func parseChannelID(raw json.RawMessage) string {
+	var channelID string
+	if err := json.Unmarshal(raw, &channelID); err == nil {
+		return channelID
+	}
+
+	channel := struct {
+		ID string `json:"id"`
+	}{}
+	if err := json.Unmarshal(raw, &channel); err == nil {
+		return channel.ID
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package events
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/slack/mock"
+)
+
+var log = zap.New()
+
+func newTestReceiver() *Receiver {
+	receiver := NewReceiver("0", mock.SigningSecret, nil, log)
+	receiver.findChannels = func(ctx context.Context, channelID string) ([]slackv1alpha1.Channel, error) {
+		if channelID != mock.PublicConversationID {
+			return nil, nil
+		}
+		return []slackv1alpha1.Channel{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-channel", Namespace: "test"},
+				Status:     slackv1alpha1.ChannelStatus{ID: channelID},
+			},
+		}, nil
+	}
+	return receiver
+}
+
+func postEvent(t *testing.T, receiver *Receiver, signingSecret string, payload string) *http.Response {
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	req, err := mock.NewSignedEventRequest(server.URL+Path, signingSecret, payload)
+	assert.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return res
+}
+
+func receiveEvent(receiver *Receiver) *event.GenericEvent {
+	select {
+	case e := <-receiver.Events():
+		return &e
+	default:
+		return nil
+	}
+}
+
+func TestReceiver_shouldRespondWithChallenge_whenURLVerification(t *testing.T) {
+	receiver := newTestReceiver()
+
+	res := postEvent(t, receiver, mock.SigningSecret, mock.URLVerificationJSON)
+	defer res.Body.Close()
+
+	body, _ := ioutil.ReadAll(res.Body)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P", string(body))
+}
+
+func TestReceiver_shouldRejectEvent_whenSignatureIsInvalid(t *testing.T) {
+	receiver := newTestReceiver()
+
+	res := postEvent(t, receiver, "wrong-secret", mock.GetChannelArchiveEventJSON(mock.PublicConversationID))
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Nil(t, receiveEvent(receiver))
+}
+
+func TestReceiver_shouldEnqueueChannel_whenChannelIsRenamed(t *testing.T) {
+	receiver := newTestReceiver()
+
+	res := postEvent(t, receiver, mock.SigningSecret, mock.GetChannelRenameEventJSON(mock.PublicConversationID, "renamed-channel"))
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	e := receiveEvent(receiver)
+	assert.NotNil(t, e)
+	assert.Equal(t, "my-channel", e.Object.GetName())
+	assert.Equal(t, "test", e.Object.GetNamespace())
+}
+
+func TestReceiver_shouldEnqueueChannel_whenChannelIsArchived(t *testing.T) {
+	receiver := newTestReceiver()
+
+	res := postEvent(t, receiver, mock.SigningSecret, mock.GetChannelArchiveEventJSON(mock.PublicConversationID))
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.NotNil(t, receiveEvent(receiver))
+}
+
+func TestReceiver_shouldIgnoreEvent_whenChannelIsNotManaged(t *testing.T) {
+	receiver := newTestReceiver()
+
+	res := postEvent(t, receiver, mock.SigningSecret, mock.GetChannelArchiveEventJSON(mock.PrivateConversationID))
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Nil(t, receiveEvent(receiver))
+}
+
+func TestReceiver_shouldNotifyUserChange_whenUserJoinsTeam(t *testing.T) {
+	receiver := newTestReceiver()
+
+	userChangedTeamID := ""
+	receiver.OnUserChange(func(teamID string) { userChangedTeamID = teamID })
+
+	res := postEvent(t, receiver, mock.SigningSecret, mock.GetTeamJoinEventJSON("W07QCRPA4", "new@slack.com"))
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "TXXXXXXXX", userChangedTeamID)
+	assert.Nil(t, receiveEvent(receiver))
+}
+
+func TestReceiver_shouldRejectEvent_whenSigningSecretIsNotLoaded(t *testing.T) {
+	receiver := newTestReceiver()
+	receiver.SetSigningSecret("")
+
+	res := postEvent(t, receiver, mock.SigningSecret, mock.GetChannelArchiveEventJSON(mock.PublicConversationID))
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	assert.Nil(t, receiveEvent(receiver))
+}
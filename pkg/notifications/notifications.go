@@ -0,0 +1,227 @@
+package notifications
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultThrottlePeriod is the interval during which the same notification is posted only once
+	DefaultThrottlePeriod = 10 * time.Minute
+
+	// DefaultLimit is the maximum number of notifications posted in a channel per throttle period
+	DefaultLimit = 20
+)
+
+// Notification is a message about a Kubernetes object that is posted in a channel
+type Notification struct {
+	// Key identifies the notification, notifications with the same key are throttled
+	Key string
+
+	// Text of the message
+	Text string
+}
+
+// PodFailure is the failure of a Pod, or of one of its containers
+type PodFailure struct {
+	// Container that failed, empty if the Pod failed as a whole
+	Container string
+
+	// Reason of the failure, e.g. CrashLoopBackOff
+	Reason string
+
+	// Message describing the failure
+	Message string
+}
+
+// containerFailureReasons are the reasons of waiting containers that are failures
+var containerFailureReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// ForEvent returns the notification of the Event, or nil if the notifications don't select it. Events that last
+// occurred before the given time are ignored, so that the events which occurred before the operator started are not
+// posted.
+func ForEvent(notifications *slackv1alpha1.EventNotifications, event *corev1.Event, since time.Time) *Notification {
+	if notifications == nil || !matchesAny(notifications.Types, event.Type) || !matchesAny(notifications.Reasons, event.Reason) {
+		return nil
+	}
+	if lastOccurrence(event).Before(since) {
+		return nil
+	}
+
+	emoji := ":information_source:"
+	if event.Type == corev1.EventTypeWarning {
+		emoji = ":warning:"
+	}
+
+	involved := event.InvolvedObject
+	return &Notification{
+		Key:  fmt.Sprintf("event/%s/%s/%s", involved.UID, event.Reason, event.Message),
+		Text: fmt.Sprintf("%s *%s* `%s` on %s `%s`: %s", emoji, event.Type, event.Reason, involved.Kind, involved.Name, event.Message),
+	}
+}
+
+// lastOccurrence returns the time at which the Event last occurred
+func lastOccurrence(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// ForPod returns the notifications of the failures of the Pod that the notifications select
+func ForPod(notifications *slackv1alpha1.PodFailureNotifications, pod *corev1.Pod) ([]Notification, error) {
+	if notifications == nil {
+		return nil, nil
+	}
+
+	selected, err := selects(notifications.Selector, pod.Labels)
+	if err != nil || !selected {
+		return nil, err
+	}
+
+	var podNotifications []Notification
+	for _, failure := range PodFailures(pod) {
+		if !matchesAny(notifications.Reasons, failure.Reason) {
+			continue
+		}
+
+		text := fmt.Sprintf(":rotating_light: Pod `%s` failed: *%s*", pod.Name, failure.Reason)
+		if failure.Container != "" {
+			text = fmt.Sprintf(":rotating_light: Container `%s` of Pod `%s` is failing: *%s*", failure.Container, pod.Name, failure.Reason)
+		}
+		if failure.Message != "" {
+			text += " " + failure.Message
+		}
+
+		podNotifications = append(podNotifications, Notification{
+			Key:  PodKeyPrefix(pod) + failure.Container + "/" + failure.Reason,
+			Text: text,
+		})
+	}
+	return podNotifications, nil
+}
+
+// PodKeyPrefix returns the prefix of the keys of the notifications of the Pod
+func PodKeyPrefix(pod *corev1.Pod) string {
+	return fmt.Sprintf("pod/%s/", pod.UID)
+}
+
+// PodFailures returns the failures of the Pod and its containers
+func PodFailures(pod *corev1.Pod) []PodFailure {
+	var failures []PodFailure
+
+	if pod.Status.Phase == corev1.PodFailed {
+		failures = append(failures, PodFailure{Reason: pod.Status.Reason, Message: pod.Status.Message})
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil && containerFailureReasons[waiting.Reason] {
+			failures = append(failures, PodFailure{Container: status.Name, Reason: waiting.Reason, Message: waiting.Message})
+		}
+
+		// A container that was OOM killed is usually waiting to be restarted, which is not a failure on its own
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+			failures = append(failures, PodFailure{Container: status.Name, Reason: terminated.Reason})
+		} else if terminated := status.State.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+			failures = append(failures, PodFailure{Container: status.Name, Reason: terminated.Reason})
+		}
+	}
+	return failures
+}
+
+// ForDeployment returns the notification of the rollout of the Deployment, or nil if the notifications don't select
+// it or its rollout is still progressing. Rollouts that ended before the given time are ignored, so that the rollouts
+// which ended before the operator started are not posted.
+func ForDeployment(notifications *slackv1alpha1.DeploymentRolloutNotifications, deployment *appsv1.Deployment, since time.Time) (*Notification, error) {
+	if notifications == nil {
+		return nil, nil
+	}
+
+	selected, err := selects(notifications.Selector, deployment.Labels)
+	if err != nil || !selected {
+		return nil, err
+	}
+
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return nil, nil
+	}
+
+	var progressing *appsv1.DeploymentCondition
+	for i := range deployment.Status.Conditions {
+		if deployment.Status.Conditions[i].Type == appsv1.DeploymentProgressing {
+			progressing = &deployment.Status.Conditions[i]
+		}
+	}
+	if progressing == nil || progressing.LastUpdateTime.Time.Before(since) {
+		return nil, nil
+	}
+
+	key := DeploymentKeyPrefix(deployment) + progressing.Reason
+
+	switch progressing.Reason {
+	case "NewReplicaSetAvailable":
+		if deployment.Status.UpdatedReplicas != deployment.Status.Replicas || deployment.Status.AvailableReplicas != deployment.Status.Replicas {
+			return nil, nil
+		}
+		return &Notification{
+			Key:  key,
+			Text: fmt.Sprintf(":white_check_mark: Deployment `%s` rolled out %d replicas", deployment.Name, deployment.Status.Replicas),
+		}, nil
+	case "ProgressDeadlineExceeded":
+		return &Notification{
+			Key:  key,
+			Text: fmt.Sprintf(":x: Deployment `%s` failed to roll out: %s", deployment.Name, progressing.Message),
+		}, nil
+	}
+	return nil, nil
+}
+
+// DeploymentKeyPrefix returns the prefix of the keys of the notifications of the current generation of the Deployment
+func DeploymentKeyPrefix(deployment *appsv1.Deployment) string {
+	return fmt.Sprintf("deployment/%s/%d/", deployment.UID, deployment.Generation)
+}
+
+// selects returns true if the label selector selects the labels, an empty selector selects everything
+func selects(selector *metav1.LabelSelector, objectLabels map[string]string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+	return labelSelector.Matches(labels.Set(objectLabels)), nil
+}
+
+// matchesAny returns true if the list is empty or contains the value
+func matchesAny(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, element := range list {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+func newEvent(eventType string, reason string, lastTimestamp time.Time) *corev1.Event {
+	return &corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1", UID: "1"},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        "Back-off restarting failed container",
+		LastTimestamp:  metav1.NewTime(lastTimestamp),
+	}
+}
+
+func newCrashLoopingPod(podLabels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", UID: "1", Labels: podLabels},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "app",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				},
+				{
+					Name:  "sidecar",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				},
+			},
+		},
+	}
+}
+
+func newRolledOutDeployment(reason string, lastUpdateTime time.Time) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", UID: "2", Generation: 3, Labels: map[string]string{"app": "web"}},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 3,
+			Replicas:           2,
+			UpdatedReplicas:    2,
+			AvailableReplicas:  2,
+			Conditions: []appsv1.DeploymentCondition{
+				{
+					Type:           appsv1.DeploymentProgressing,
+					Status:         corev1.ConditionTrue,
+					Reason:         reason,
+					LastUpdateTime: metav1.NewTime(lastUpdateTime),
+				},
+			},
+		},
+	}
+}
+
+func TestForEvent_shouldFilterByTypeAndReason(t *testing.T) {
+	started := time.Now().Add(-time.Minute)
+	filter := &slackv1alpha1.EventNotifications{Types: []string{corev1.EventTypeWarning}, Reasons: []string{"BackOff"}}
+
+	assert.NotNil(t, ForEvent(filter, newEvent(corev1.EventTypeWarning, "BackOff", time.Now()), started))
+	assert.Nil(t, ForEvent(filter, newEvent(corev1.EventTypeNormal, "BackOff", time.Now()), started))
+	assert.Nil(t, ForEvent(filter, newEvent(corev1.EventTypeWarning, "FailedScheduling", time.Now()), started))
+}
+
+func TestForEvent_shouldIgnoreEventsBeforeStart(t *testing.T) {
+	started := time.Now()
+
+	assert.Nil(t, ForEvent(&slackv1alpha1.EventNotifications{}, newEvent(corev1.EventTypeWarning, "BackOff", started.Add(-time.Hour)), started))
+}
+
+func TestForPod_shouldReturnFailingContainers(t *testing.T) {
+	notifications, err := ForPod(&slackv1alpha1.PodFailureNotifications{}, newCrashLoopingPod(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []Notification{
+		{Key: "pod/1/app/CrashLoopBackOff", Text: ":rotating_light: Container `app` of Pod `web-1` is failing: *CrashLoopBackOff*"},
+	}, notifications)
+}
+
+func TestForPod_shouldFilterByReasonAndSelector(t *testing.T) {
+	pod := newCrashLoopingPod(map[string]string{"app": "web"})
+
+	notifications, err := ForPod(&slackv1alpha1.PodFailureNotifications{Reasons: []string{"OOMKilled"}}, pod)
+	assert.NoError(t, err)
+	assert.Empty(t, notifications)
+
+	notifications, err = ForPod(&slackv1alpha1.PodFailureNotifications{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}}, pod)
+	assert.NoError(t, err)
+	assert.Empty(t, notifications)
+}
+
+func TestPodFailures_shouldReturnOOMKilledContainers(t *testing.T) {
+	pod := newCrashLoopingPod(nil)
+	pod.Status.ContainerStatuses[1].LastTerminationState = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}}
+
+	assert.Equal(t, []PodFailure{
+		{Container: "app", Reason: "CrashLoopBackOff"},
+		{Container: "sidecar", Reason: "OOMKilled"},
+	}, PodFailures(pod))
+}
+
+func TestForDeployment_shouldReturnCompletedRollout(t *testing.T) {
+	started := time.Now().Add(-time.Minute)
+
+	notification, err := ForDeployment(&slackv1alpha1.DeploymentRolloutNotifications{}, newRolledOutDeployment("NewReplicaSetAvailable", time.Now()), started)
+	assert.NoError(t, err)
+	assert.Equal(t, &Notification{Key: "deployment/2/3/NewReplicaSetAvailable", Text: ":white_check_mark: Deployment `web` rolled out 2 replicas"}, notification)
+}
+
+func TestForDeployment_shouldIgnoreProgressingRollout(t *testing.T) {
+	started := time.Now().Add(-time.Minute)
+
+	notification, err := ForDeployment(&slackv1alpha1.DeploymentRolloutNotifications{}, newRolledOutDeployment("ReplicaSetUpdated", time.Now()), started)
+	assert.NoError(t, err)
+	assert.Nil(t, notification)
+}
+
+func TestForDeployment_shouldIgnoreRolloutsBeforeStart(t *testing.T) {
+	started := time.Now()
+
+	notification, err := ForDeployment(&slackv1alpha1.DeploymentRolloutNotifications{}, newRolledOutDeployment("NewReplicaSetAvailable", started.Add(-time.Hour)), started)
+	assert.NoError(t, err)
+	assert.Nil(t, notification)
+}
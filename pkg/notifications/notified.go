@@ -0,0 +1,53 @@
+package notifications
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Notified holds the notifications that were posted about an object by the channel ID and the key of the
+// notification, it is recorded in a ConfigMap of the operator
+type Notified map[string]bool
+
+// ParseNotified returns the notifications recorded in the value, an invalid value is treated as empty
+func ParseNotified(value string) Notified {
+	var entries []string
+	_ = json.Unmarshal([]byte(value), &entries)
+
+	notified := Notified{}
+	for _, entry := range entries {
+		notified[entry] = true
+	}
+	return notified
+}
+
+// Has returns true if the notification was posted in the channel
+func (n Notified) Has(channelID string, key string) bool {
+	return n[channelID+"/"+key]
+}
+
+// Add records that the notification was posted in the channel
+func (n Notified) Add(channelID string, key string) {
+	n[channelID+"/"+key] = true
+}
+
+// Value returns the value the notifications are recorded in, or an empty string if no notification was posted. Only the
+// notifications whose keys have the prefix are kept, so that e.g. those of earlier generations of a Deployment are
+// forgotten.
+func (n Notified) Value(keyPrefix string) string {
+	var entries []string
+	for entry := range n {
+		key := entry[strings.Index(entry, "/")+1:]
+		if strings.HasPrefix(key, keyPrefix) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+
+	sort.Strings(entries)
+	value, _ := json.Marshal(entries)
+	return string(value)
+}
@@ -0,0 +1,29 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNotified_shouldReturnRecordedNotifications(t *testing.T) {
+	notified := ParseNotified(`["C0123/pod/1/app/CrashLoopBackOff"]`)
+
+	assert.True(t, notified.Has("C0123", "pod/1/app/CrashLoopBackOff"))
+	assert.False(t, notified.Has("C0456", "pod/1/app/CrashLoopBackOff"))
+}
+
+func TestParseNotified_shouldIgnoreInvalidValue(t *testing.T) {
+	assert.Empty(t, ParseNotified("C0123/pod/1/app/CrashLoopBackOff"))
+	assert.Empty(t, ParseNotified(""))
+}
+
+func TestNotified_Value_shouldForgetNotificationsWithoutPrefix(t *testing.T) {
+	notified := Notified{}
+	notified.Add("C0456", "deployment/2/3/NewReplicaSetAvailable")
+	notified.Add("C0123", "deployment/2/3/ProgressDeadlineExceeded")
+	notified.Add("C0123", "deployment/2/2/NewReplicaSetAvailable")
+
+	assert.Equal(t, `["C0123/deployment/2/3/ProgressDeadlineExceeded","C0456/deployment/2/3/NewReplicaSetAvailable"]`, notified.Value("deployment/2/3/"))
+	assert.Equal(t, "", notified.Value("deployment/2/4/"))
+}
@@ -0,0 +1,83 @@
+package notifications
+
+import (
+	"sync"
+	"time"
+)
+
+// Throttle posts the same notification only once per throttle period and limits the number of notifications per
+// channel, so that e.g. crash loops don't flood the channels
+type Throttle struct {
+	mutex sync.Mutex
+	now   func() time.Time
+
+	// posted holds the time until which each notification of each channel is throttled
+	posted map[string]time.Time
+
+	// windows holds the times at which notifications were posted in each channel during the last throttle period
+	windows map[string][]time.Time
+}
+
+// NewThrottle creates a new Throttle
+func NewThrottle() *Throttle {
+	return &Throttle{
+		now:     time.Now,
+		posted:  map[string]time.Time{},
+		windows: map[string][]time.Time{},
+	}
+}
+
+// Allow returns true if the notification with the key can be posted in the channel, in which case it is recorded as
+// posted. Call Forget if posting it failed.
+func (t *Throttle) Allow(channelID string, key string, period time.Duration, limit int) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	t.expire(now)
+
+	notificationKey := channelID + "/" + key
+	if until, ok := t.posted[notificationKey]; ok && now.Before(until) {
+		return false
+	}
+
+	var window []time.Time
+	for _, postedAt := range t.windows[channelID] {
+		if now.Sub(postedAt) < period {
+			window = append(window, postedAt)
+		}
+	}
+	if limit > 0 && len(window) >= limit {
+		t.windows[channelID] = window
+		return false
+	}
+
+	t.posted[notificationKey] = now.Add(period)
+	t.windows[channelID] = append(window, now)
+	return true
+}
+
+// Forget removes a notification that could not be posted, so that it is allowed again
+func (t *Throttle) Forget(channelID string, key string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	notificationKey := channelID + "/" + key
+	if _, ok := t.posted[notificationKey]; !ok {
+		return
+	}
+	delete(t.posted, notificationKey)
+
+	if window := t.windows[channelID]; len(window) > 0 {
+		t.windows[channelID] = window[:len(window)-1]
+	}
+}
+
+// expire removes the notifications that are no longer throttled
+func (t *Throttle) expire(now time.Time) {
+	for key, until := range t.posted {
+		if !now.Before(until) {
+			delete(t.posted, key)
+		}
+	}
+}
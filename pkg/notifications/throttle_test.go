@@ -0,0 +1,47 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestThrottle(now *time.Time) *Throttle {
+	throttle := NewThrottle()
+	throttle.now = func() time.Time { return *now }
+	return throttle
+}
+
+func TestThrottle_Allow_shouldThrottleSameNotification_duringThrottlePeriod(t *testing.T) {
+	now := time.Now()
+	throttle := newTestThrottle(&now)
+
+	assert.True(t, throttle.Allow("C0123", "pod/1/app/CrashLoopBackOff", 10*time.Minute, 0))
+	assert.False(t, throttle.Allow("C0123", "pod/1/app/CrashLoopBackOff", 10*time.Minute, 0))
+	assert.True(t, throttle.Allow("C0456", "pod/1/app/CrashLoopBackOff", 10*time.Minute, 0))
+
+	now = now.Add(10 * time.Minute)
+	assert.True(t, throttle.Allow("C0123", "pod/1/app/CrashLoopBackOff", 10*time.Minute, 0))
+}
+
+func TestThrottle_Allow_shouldLimitNotificationsPerChannel(t *testing.T) {
+	now := time.Now()
+	throttle := newTestThrottle(&now)
+
+	assert.True(t, throttle.Allow("C0123", "pod/1/app/CrashLoopBackOff", time.Minute, 2))
+	assert.True(t, throttle.Allow("C0123", "pod/2/app/CrashLoopBackOff", time.Minute, 2))
+	assert.False(t, throttle.Allow("C0123", "pod/3/app/CrashLoopBackOff", time.Minute, 2))
+
+	now = now.Add(time.Minute)
+	assert.True(t, throttle.Allow("C0123", "pod/3/app/CrashLoopBackOff", time.Minute, 2))
+}
+
+func TestThrottle_Forget_shouldAllowNotificationAgain(t *testing.T) {
+	now := time.Now()
+	throttle := newTestThrottle(&now)
+
+	assert.True(t, throttle.Allow("C0123", "pod/1/app/CrashLoopBackOff", time.Minute, 1))
+	throttle.Forget("C0123", "pod/1/app/CrashLoopBackOff")
+	assert.True(t, throttle.Allow("C0123", "pod/1/app/CrashLoopBackOff", time.Minute, 1))
+}
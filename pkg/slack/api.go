@@ -0,0 +1,59 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// apiResponse is the response of a slack API method, which reports whether the call failed
+type apiResponse interface {
+	Err() error
+}
+
+// callAPI posts the form to a slack API method that the slack client doesn't support, e.g. the admin methods, and
+// decodes the response. Requests go through the rate limited client and fail like the ones of the slack client.
+func (s *SlackService) callAPI(method string, values url.Values, response apiResponse) error {
+	api := s.api.Load().(*connection)
+
+	form := url.Values{"token": {api.token}}
+	for key, value := range values {
+		form[key] = value
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.apiURL+method, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := strconv.ParseInt(res.Header.Get("Retry-After"), 10, 64)
+		return &slack.RateLimitedError{RetryAfter: time.Duration(retryAfter) * time.Second}
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack server error: %s", res.Status)
+	}
+
+	if response == nil {
+		response = &slack.SlackResponse{}
+	}
+	err = json.NewDecoder(res.Body).Decode(response)
+	if err != nil {
+		return err
+	}
+
+	return response.Err()
+}
@@ -0,0 +1,58 @@
+package slack
+
+import (
+	"sync"
+)
+
+// ServiceCache caches a Service per slack workspace, so that the Channels of a workspace share its user directory
+// and rate limits
+type ServiceCache struct {
+	newService func(token string) Service
+
+	mutex    sync.Mutex
+	services map[string]cachedService
+}
+
+type cachedService struct {
+	token   string
+	service Service
+}
+
+// NewServiceCache creates a ServiceCache which creates the Service of a workspace with newService
+func NewServiceCache(newService func(token string) Service) *ServiceCache {
+	return &ServiceCache{
+		newService: newService,
+		services:   map[string]cachedService{},
+	}
+}
+
+// Get returns the Service of the workspace, a new Service is created when the token of the workspace changed
+func (c *ServiceCache) Get(workspace string, token string) Service {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cached, ok := c.services[workspace]
+	if !ok || cached.token != token {
+		cached = cachedService{token: token, service: c.newService(token)}
+		c.services[workspace] = cached
+	}
+	return cached.service
+}
+
+// Delete removes the Service of the workspace
+func (c *ServiceCache) Delete(workspace string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.services, workspace)
+}
+
+// InvalidateUsers expires the cached users of the Services of the workspace with the ID
+func (c *ServiceCache) InvalidateUsers(teamID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, cached := range c.services {
+		cached.service.InvalidateUsers(teamID)
+	}
+}
@@ -0,0 +1,188 @@
+package slack
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// usersListPageSize is the number of users requested per page of users.list
+const usersListPageSize = 200
+
+// usersNotFoundError is the error of users.lookupByEmail for emails without a user
+const usersNotFoundError = "users_not_found"
+
+// userDirectory caches the users of the slack workspace by ID and email, so that membership checks don't look up
+// every member of every channel on every reconcile. It is loaded from users.list and expires after its TTL or when
+// invalidated, e.g. by a team_join or user_change event.
+type userDirectory struct {
+	ttl       time.Duration
+	listUsers func() ([]slack.User, error)
+
+	// refreshMutex serializes the loads, which list the users without holding mutex so that lookups of the loaded
+	// users are not blocked while users.list is paged through
+	refreshMutex sync.Mutex
+
+	mutex     sync.RWMutex
+	byID      map[string]slack.User
+	byEmail   map[string]slack.User
+	expiresAt time.Time
+
+	// invalidations counts the calls of Invalidate, a load during which the directory was invalidated stays expired
+	invalidations int
+
+	// notFound holds the emails an individual lookup didn't find, so that they are not looked up on every reconcile
+	// until the directory is reloaded
+	notFound map[string]bool
+}
+
+func newUserDirectory(ttl time.Duration, listUsers func() ([]slack.User, error)) *userDirectory {
+	return &userDirectory{
+		ttl:       ttl,
+		listUsers: listUsers,
+		byID:      map[string]slack.User{},
+		byEmail:   map[string]slack.User{},
+		notFound:  map[string]bool{},
+	}
+}
+
+// listAllUsers returns all users of the workspace, following the pagination of users.list
+func listAllUsers(api *slack.Client) ([]slack.User, error) {
+	var users []slack.User
+
+	page := api.GetUsersPaginated(slack.GetUsersOptionLimit(usersListPageSize))
+	for {
+		var err error
+		page, err = page.Next(context.Background())
+		if page.Done(err) {
+			return users, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, page.Users...)
+	}
+}
+
+// GetByID returns the user with the given ID, found is false if the directory doesn't contain the user
+func (d *userDirectory) GetByID(id string) (user slack.User, found bool, err error) {
+	err = d.refresh()
+	if err != nil {
+		return user, false, err
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	user, found = d.byID[id]
+	return user, found, nil
+}
+
+// GetByEmail returns the user with the given email, found is false if the directory doesn't contain the user
+func (d *userDirectory) GetByEmail(email string) (user slack.User, found bool, err error) {
+	err = d.refresh()
+	if err != nil {
+		return user, false, err
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	user, found = d.byEmail[strings.ToLower(email)]
+	return user, found, nil
+}
+
+// Add adds a user that was looked up individually, e.g. because it joined after the directory was loaded
+func (d *userDirectory) Add(user slack.User) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.add(user)
+}
+
+// IsNotFound returns true if an individual lookup didn't find a user with the given email since the directory was loaded
+func (d *userDirectory) IsNotFound(email string) bool {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	return d.notFound[strings.ToLower(email)]
+}
+
+// AddNotFound records that an individual lookup didn't find a user with the given email
+func (d *userDirectory) AddNotFound(email string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.notFound[strings.ToLower(email)] = true
+}
+
+// Invalidate expires the directory so that it is reloaded on the next lookup
+func (d *userDirectory) Invalidate() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.expiresAt = time.Time{}
+	d.invalidations++
+}
+
+func (d *userDirectory) refresh() error {
+	expired, _ := d.expired()
+	if !expired {
+		return nil
+	}
+
+	d.refreshMutex.Lock()
+	defer d.refreshMutex.Unlock()
+
+	// Another lookup may have reloaded the directory while waiting for the lock
+	expired, invalidations := d.expired()
+	if !expired {
+		return nil
+	}
+
+	users, err := d.listUsers()
+	if err != nil {
+		return err
+	}
+
+	byID := map[string]slack.User{}
+	byEmail := map[string]slack.User{}
+	for _, user := range users {
+		byID[user.ID] = user
+		if user.Profile.Email != "" {
+			byEmail[strings.ToLower(user.Profile.Email)] = user
+		}
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.byID = byID
+	d.byEmail = byEmail
+	d.notFound = map[string]bool{}
+	if d.invalidations == invalidations {
+		d.expiresAt = time.Now().Add(d.ttl)
+	}
+
+	return nil
+}
+
+// expired returns true if the directory has to be reloaded, and the number of invalidations so far
+func (d *userDirectory) expired() (bool, int) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	return time.Now().After(d.expiresAt), d.invalidations
+}
+
+// add adds the user to the directory, it must be called with the mutex held
+func (d *userDirectory) add(user slack.User) {
+	d.byID[user.ID] = user
+	if user.Profile.Email != "" {
+		d.byEmail[strings.ToLower(user.Profile.Email)] = user
+		delete(d.notFound, strings.ToLower(user.Profile.Email))
+	}
+}
@@ -0,0 +1,136 @@
+package slack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stakater/slack-operator/pkg/slack/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestUserDirectory(ttl time.Duration) (*userDirectory, *int) {
+	loads := 0
+	directory := newUserDirectory(ttl, func() ([]slack.User, error) {
+		loads++
+		return []slack.User{
+			{ID: "W012A3CDE", Profile: slack.UserProfile{Email: mock.ExistingUserEmail}},
+			{ID: "U023BECGF", IsBot: true},
+		}, nil
+	})
+	return directory, &loads
+}
+
+func TestUserDirectory_GetByEmail_shouldReturnUser_whenUserExists(t *testing.T) {
+	directory, _ := newTestUserDirectory(time.Hour)
+
+	user, found, err := directory.GetByEmail("IAmUser@slack.com")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "W012A3CDE", user.ID)
+}
+
+func TestUserDirectory_GetByID_shouldNotFindUser_whenUserDoesNotExist(t *testing.T) {
+	directory, _ := newTestUserDirectory(time.Hour)
+
+	_, found, err := directory.GetByID(mock.NotFoundUserID)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestUserDirectory_shouldLoadOnce_whenNotExpired(t *testing.T) {
+	directory, loads := newTestUserDirectory(time.Hour)
+
+	_, _, _ = directory.GetByID("W012A3CDE")
+	_, _, _ = directory.GetByEmail(mock.ExistingUserEmail)
+	assert.Equal(t, 1, *loads)
+}
+
+func TestUserDirectory_shouldReload_whenExpired(t *testing.T) {
+	directory, loads := newTestUserDirectory(0)
+
+	_, _, _ = directory.GetByID("W012A3CDE")
+	_, _, _ = directory.GetByID("W012A3CDE")
+	assert.Equal(t, 2, *loads)
+}
+
+func TestUserDirectory_shouldReload_whenInvalidated(t *testing.T) {
+	directory, loads := newTestUserDirectory(time.Hour)
+
+	_, _, _ = directory.GetByID("W012A3CDE")
+	directory.Invalidate()
+	_, _, _ = directory.GetByID("W012A3CDE")
+	assert.Equal(t, 2, *loads)
+}
+
+func TestUserDirectory_shouldReload_whenInvalidatedWhileLoading(t *testing.T) {
+	loads := 0
+	var directory *userDirectory
+	directory = newUserDirectory(time.Hour, func() ([]slack.User, error) {
+		loads++
+		if loads == 1 {
+			directory.Invalidate()
+		}
+		return nil, nil
+	})
+
+	_, _, _ = directory.GetByID("W012A3CDE")
+	_, _, _ = directory.GetByID("W012A3CDE")
+	assert.Equal(t, 2, loads)
+}
+
+func TestUserDirectory_shouldNotBlockOtherLookups_whileLoading(t *testing.T) {
+	listing := make(chan bool)
+	release := make(chan bool)
+	directory := newUserDirectory(time.Hour, func() ([]slack.User, error) {
+		close(listing)
+		<-release
+		return nil, nil
+	})
+
+	loaded := make(chan bool)
+	go func() {
+		_, _, _ = directory.GetByID("W012A3CDE")
+		close(loaded)
+	}()
+
+	<-listing
+	directory.AddNotFound("new@slack.com")
+	assert.True(t, directory.IsNotFound("new@slack.com"))
+
+	close(release)
+	<-loaded
+}
+
+func TestUserDirectory_Add_shouldAddUser_whenUserJoinedAfterLoad(t *testing.T) {
+	directory, loads := newTestUserDirectory(time.Hour)
+
+	_, _, _ = directory.GetByID("W012A3CDE")
+	directory.Add(slack.User{ID: "W07QCRPA4", Profile: slack.UserProfile{Email: "new@slack.com"}})
+
+	user, found, err := directory.GetByEmail("new@slack.com")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "W07QCRPA4", user.ID)
+	assert.Equal(t, 1, *loads)
+}
+
+func TestUserDirectory_IsNotFound_shouldForgetEmails_whenReloaded(t *testing.T) {
+	directory, _ := newTestUserDirectory(time.Hour)
+
+	_, _, _ = directory.GetByEmail("new@slack.com")
+	directory.AddNotFound("New@slack.com")
+	assert.True(t, directory.IsNotFound("new@slack.com"))
+
+	directory.Invalidate()
+	_, _, _ = directory.GetByEmail("new@slack.com")
+	assert.False(t, directory.IsNotFound("new@slack.com"))
+}
+
+func TestListAllUsers_shouldFollowPagination(t *testing.T) {
+	s := NewMockService(log)
+
+	users, err := listAllUsers(s.client())
+	assert.NoError(t, err)
+	assert.Len(t, users, 5)
+}
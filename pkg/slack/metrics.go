@@ -0,0 +1,46 @@
+package slack
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "slack_api_requests_total",
+		Help: "Number of requests sent to the slack API",
+	}, []string{"method"})
+
+	rateLimitedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "slack_api_rate_limited_total",
+		Help: "Number of requests to the slack API rejected by slack with a rate limit error",
+	}, []string{"method"})
+
+	throttledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "slack_api_throttled_total",
+		Help: "Number of requests to the slack API not sent because of the client side rate limit or a pending Retry-After",
+	}, []string{"method"})
+
+	throttleWaitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "slack_api_throttle_wait_seconds",
+		Help:    "Time requests to the slack API waited for the client side rate limit",
+		Buckets: []float64{0, 0.1, 0.5, 1, 2.5, 5, 10},
+	}, []string{"method"})
+)
+
+// RegisterTokenMetric reports whether the service has a valid API token in the slack_api_token_valid metric
+func RegisterTokenMetric(s Service) error {
+	return metrics.Registry.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "slack_api_token_valid",
+		Help: "Whether the operator has a valid slack API token, 1 if it has one and 0 otherwise",
+	}, func() float64 {
+		if s.TokenError() != nil {
+			return 0
+		}
+		return 1
+	}))
+}
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal, rateLimitedTotal, throttledTotal, throttleWaitSeconds)
+}
@@ -0,0 +1,73 @@
+package mock
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Call is a request received by the mock slack API
+type Call struct {
+	// Method is the slack API method, e.g. conversations.archive
+	Method string
+
+	// Params are the form parameters of the request
+	Params url.Values
+}
+
+var (
+	callsMutex sync.Mutex
+	calls      []Call
+)
+
+// Calls returns the requests received for the method since the last ResetCalls, in the order they were received
+func Calls(method string) []Call {
+	callsMutex.Lock()
+	defer callsMutex.Unlock()
+
+	var methodCalls []Call
+	for _, call := range calls {
+		if call.Method == method {
+			methodCalls = append(methodCalls, call)
+		}
+	}
+	return methodCalls
+}
+
+// Methods returns the slack API methods called since the last ResetCalls, in the order they were called
+func Methods() []string {
+	callsMutex.Lock()
+	defer callsMutex.Unlock()
+
+	var methods []string
+	for _, call := range calls {
+		methods = append(methods, call.Method)
+	}
+	return methods
+}
+
+// ResetCalls forgets the requests received so far
+func ResetCalls() {
+	callsMutex.Lock()
+	defer callsMutex.Unlock()
+
+	calls = nil
+}
+
+// recorded records the requests before passing them to the handler
+func recorded(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+		params, _ := url.ParseQuery(string(body))
+
+		callsMutex.Lock()
+		calls = append(calls, Call{Method: strings.TrimPrefix(r.URL.Path, "/"), Params: params})
+		callsMutex.Unlock()
+
+		handler(w, r)
+	}
+}
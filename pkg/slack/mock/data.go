@@ -11,6 +11,7 @@ var ChannelObjectModifiedError = "Operation cannot be fulfilled on channels.slac
 
 var ConversationName = "bat-channel"
 var NameTakenConversationName = "name-taken"
+var AdoptableConversationName = "adoptable-channel"
 var InvalidChannelName = "#$%^^^&)$#(!($&!#KHLREJOIWQRHQOIWRHQWRIOWQIHEIUWQ BE&#84y2180943u20932"
 var PublicConversationID = "C0EAQDV4Z"
 var PrivateConversationID = "Y7HGFWC6Q"
@@ -80,6 +81,16 @@ var privateConversationJSON = fmt.Sprintf(templateConversationJSON, PrivateConve
 var inviteConversationJSON = fmt.Sprintf(templateConversationJSON, PublicConversationID, ConversationName,
 	nowAsJSONTime(), BotID, ConversationName, "false", "", "", 0, "", "", 0, 1)
 
+var listConversationsJSON = fmt.Sprintf(`
+	{
+		"ok": true,
+		"channels": [%s],
+		"response_metadata": {
+			"next_cursor": ""
+		}
+	}`, fmt.Sprintf(templateChannelJSON, PublicConversationID, AdoptableConversationName,
+	nowAsJSONTime(), BotID, AdoptableConversationName, "false", "", "", 0, "", "", 0, 3))
+
 func getConversationNameResponse(name string) string {
 	return fmt.Sprintf(templateConversationJSON, PublicConversationID, name,
 		nowAsJSONTime(), BotID, name, "false", "", "", 0, "", "", 0, 0)
@@ -182,13 +193,179 @@ var membersInConversationJSON = `
 	"members": [
 		"U023BECGF",
 		"U061F7AUR",
-		"W012A3CDE"
+		"W012A3CDE",
+		"W0VENDOR1"
 	],
 	"response_metadata": {
 		"next_cursor": "e3VzZXJfaWQ6IFcxMjM0NTY3fQ=="
 	}
 }`
 
+// DeactivatedUserEmail is the email of a deactivated user in users.list
+const DeactivatedUserEmail = "deactivated@slack.com"
+
+// ExternalUserEmail is the email of a member of the public channel which belongs to the organization ExternalTeamID
+const ExternalUserEmail = "vendor@vendor.example.com"
+const ExternalTeamID = "T0VENDOR1"
+
+const usersListNextCursor = "dXNlcjpVMDYxRjdBVVI"
+
+// users.list returns the members of the mock conversations on two pages
+var usersListFirstPageJSON = fmt.Sprintf(`
+{
+	"ok": true,
+	"members": [
+		{
+			"id": "%s",
+			"name": "bot",
+			"deleted": false,
+			"is_bot": true,
+			"profile": {}
+		},
+		{
+			"id": "U061F7AUR",
+			"name": "venkman",
+			"deleted": false,
+			"is_bot": false,
+			"profile": {
+				"email": "venkman@ghostbusters.example.com"
+			}
+		}
+	],
+	"response_metadata": {
+		"next_cursor": "%s"
+	}
+}`, BotID, usersListNextCursor)
+
+var usersListLastPageJSON = fmt.Sprintf(`
+{
+	"ok": true,
+	"members": [
+		{
+			"id": "W012A3CDE",
+			"name": "spengler",
+			"deleted": false,
+			"is_bot": false,
+			"profile": {
+				"email": "%s"
+			}
+		},
+		{
+			"id": "W07QCRPA4",
+			"name": "stantz",
+			"deleted": true,
+			"is_bot": false,
+			"profile": {
+				"email": "%s"
+			}
+		},
+		{
+			"id": "W0VENDOR1",
+			"team_id": "%s",
+			"name": "vendor",
+			"deleted": false,
+			"is_bot": false,
+			"profile": {
+				"email": "%s"
+			}
+		}
+	],
+	"response_metadata": {
+		"next_cursor": ""
+	}
+}`, ExistingUserEmail, DeactivatedUserEmail, ExternalTeamID, ExternalUserEmail)
+
+// InvalidToken is an API token which is rejected by auth.test
+const InvalidToken = "xoxb-invalid"
+
+// TeamID is the ID of the workspace returned by auth.test
+const TeamID = "T012AB3C4"
+
+var authTestJSON = fmt.Sprintf(`
+{
+	"ok": true,
+	"url": "https://ghostbusters.slack.com/",
+	"team": "Ghostbusters",
+	"user": "bot",
+	"team_id": "%s",
+	"user_id": "%s",
+	"bot_id": "B0123ABCD"
+}`, TeamID, BotID)
+
+var invalidAuthJSON = `
+{
+	"ok": false,
+	"error": "invalid_auth"
+}`
+
+// UserGroupID and UserGroupHandle identify the user group returned by usergroups.list, whose members are the users
+// of users.list except the bot
+const UserGroupID = "S0614TZR7"
+const UserGroupHandle = "ghostbusters"
+
+// NewUserGroupID is the ID of the user groups created by usergroups.create, which are listed with a single member
+const NewUserGroupID = "S0615G0KT"
+
+// NotFoundUserGroupID is the ID of a user group which does not exist
+const NotFoundUserGroupID = "-"
+
+var userGroupsListJSON = fmt.Sprintf(`
+{
+	"ok": true,
+	"usergroups": [
+		{
+			"id": "%s",
+			"team_id": "%s",
+			"is_usergroup": true,
+			"name": "Ghostbusters",
+			"description": "Who you gonna call",
+			"handle": "%s",
+			"users": [
+				"U061F7AUR",
+				"W012A3CDE",
+				"W07QCRPA4"
+			],
+			"user_count": 3
+		},
+		{
+			"id": "%s",
+			"team_id": "%s",
+			"is_usergroup": true,
+			"name": "Ghostbusters On-Call",
+			"description": "",
+			"handle": "ghostbusters-oncall",
+			"users": [
+				"W012A3CDE"
+			],
+			"user_count": 1
+		}
+	]
+}`, UserGroupID, TeamID, UserGroupHandle, NewUserGroupID, TeamID)
+
+var templateUserGroupResponseJSON = fmt.Sprintf(`
+{
+	"ok": true,
+	"usergroup": {
+		"id": "%%s",
+		"team_id": "%s",
+		"is_usergroup": true,
+		"name": "Ghostbusters",
+		"handle": "%%s"
+	}
+}`, TeamID)
+
+var userGroupNameTakenJSON = `
+{
+	"ok": false,
+	"error": "name_already_exists"
+}`
+
+var userGroupNotFoundJSON = `
+{
+	"ok": false,
+	"error": "no_such_subteam"
+}`
+
 var userNotFoundJSON = `
 {
     "ok": false,
@@ -196,6 +373,109 @@ var userNotFoundJSON = `
 }
 `
 
+// InviteID is the ID of the invitations sent by conversations.inviteShared
+const InviteID = "I02UKAJ6RJA"
+
+var inviteSharedJSON = fmt.Sprintf(`
+{
+	"ok": true,
+	"invite_id": "%s",
+	"is_legacy_shared_channel": false
+}`, InviteID)
+
+// BookmarkID is the ID of the bookmark returned by bookmarks.list
+const BookmarkID = "Bk01ABCDEFGH"
+const BookmarkTitle = "Runbook"
+const BookmarkLink = "https://runbooks.example.com"
+
+// NewBookmarkID is the ID of the bookmarks added by bookmarks.add
+const NewBookmarkID = "Bk02ABCDEFGH"
+
+var listBookmarksJSON = fmt.Sprintf(`
+{
+	"ok": true,
+	"bookmarks": [
+		{
+			"id": "%s",
+			"title": "%s",
+			"link": "%s",
+			"emoji": ":book:",
+			"type": "link"
+		}
+	]
+}`, BookmarkID, BookmarkTitle, BookmarkLink)
+
+func getBookmarkResponse(id string, title string, link string, emoji string) string {
+	return fmt.Sprintf(`
+{
+	"ok": true,
+	"bookmark": {
+		"id": "%s",
+		"title": "%s",
+		"link": "%s",
+		"emoji": "%s",
+		"type": "link"
+	}
+}`, id, title, link, emoji)
+}
+
+var bookmarkNotFoundJSON = `
+{
+	"ok": false,
+	"error": "not_found"
+}`
+
+// PinnedMessageTimestamp is the timestamp of the message returned by pins.list
+const PinnedMessageTimestamp = "1503435956.000247"
+const PinnedMessageText = "Follow the runbook"
+
+// NewMessageTimestamp is the timestamp of the messages posted by chat.postMessage
+const NewMessageTimestamp = "1503435957.000248"
+
+func getMessageResponse(channelID string, timestamp string) string {
+	return fmt.Sprintf(`
+{
+	"ok": true,
+	"channel": "%s",
+	"ts": "%s"
+}`, channelID, timestamp)
+}
+
+func getListPinsResponse(channelID string) string {
+	return fmt.Sprintf(`
+{
+	"ok": true,
+	"items": [
+		{
+			"type": "message",
+			"channel": "%s",
+			"message": {
+				"type": "message",
+				"text": "%s",
+				"ts": "%s"
+			}
+		}
+	]
+}`, channelID, PinnedMessageText, PinnedMessageTimestamp)
+}
+
+var messageNotFoundJSON = `
+{
+	"ok": false,
+	"error": "message_not_found"
+}`
+
+var okJSON = `
+{
+	"ok": true
+}`
+
+var invalidPrefsJSON = `
+{
+	"ok": false,
+	"error": "invalid_prefs"
+}`
+
 var channelNotFoundJSON = `
 {
 	"ok": false,
@@ -0,0 +1,87 @@
+package mock
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var SigningSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+var templateEventCallbackJSON = `
+{
+	"token": "XXYYZZ",
+	"team_id": "TXXXXXXXX",
+	"api_app_id": "AXXXXXXXXX",
+	"event": %s,
+	"type": "event_callback",
+	"event_id": "Ev08MFMKH6",
+	"event_time": 1234567890
+}`
+
+var URLVerificationJSON = `
+{
+	"token": "Jhj5dZrVaK7ZwHHjRyZWjbDl",
+	"challenge": "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P",
+	"type": "url_verification"
+}`
+
+// GetChannelRenameEventJSON returns a channel_rename event for the given channel
+func GetChannelRenameEventJSON(channelID string, name string) string {
+	return fmt.Sprintf(templateEventCallbackJSON, fmt.Sprintf(`
+	{
+		"type": "channel_rename",
+		"channel": {
+			"id": "%s",
+			"name": "%s",
+			"created": 1360782804
+		}
+	}`, channelID, name))
+}
+
+// GetChannelArchiveEventJSON returns a channel_archive event for the given channel
+func GetChannelArchiveEventJSON(channelID string) string {
+	return fmt.Sprintf(templateEventCallbackJSON, fmt.Sprintf(`
+	{
+		"type": "channel_archive",
+		"channel": "%s",
+		"user": "W012A3CDE"
+	}`, channelID))
+}
+
+// GetTeamJoinEventJSON returns a team_join event for the given user
+func GetTeamJoinEventJSON(userID string, email string) string {
+	return fmt.Sprintf(templateEventCallbackJSON, fmt.Sprintf(`
+	{
+		"type": "team_join",
+		"user": {
+			"id": "%s",
+			"profile": {
+				"email": "%s"
+			}
+		}
+	}`, userID, email))
+}
+
+// NewSignedEventRequest creates a request posting the given payload to url, signed like the slack Events API does
+func NewSignedEventRequest(url string, signingSecret string, payload string) (*http.Request, error) {
+	timestamp := fmt.Sprintf("%d", time.Now().Unix())
+
+	hash := hmac.New(sha256.New, []byte(signingSecret))
+	_, _ = hash.Write([]byte("v0:" + timestamp + ":" + payload))
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(hash.Sum(nil)))
+
+	return req, nil
+}
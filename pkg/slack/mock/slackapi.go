@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 
 	"github.com/slack-go/slack/slacktest"
 )
@@ -16,34 +17,103 @@ func InitSlackTestServer() *slacktest.Server {
 
 	testServer := slacktest.NewTestServer(
 		func(c slacktest.Customize) {
-			c.Handle("/conversations.info", conversationInfoHandler)
+			c.Handle("/conversations.info", recorded(conversationInfoHandler))
 		},
 		func(c slacktest.Customize) {
-			c.Handle("/conversations.create", createConversationHandler)
+			c.Handle("/conversations.create", recorded(createConversationHandler))
 		},
 		func(c slacktest.Customize) {
-			c.Handle("/conversations.setTopic", setConversationTopicHandler)
+			c.Handle("/conversations.setTopic", recorded(setConversationTopicHandler))
 		},
 		func(c slacktest.Customize) {
-			c.Handle("/conversations.setPurpose", setConversationPurposeHandler)
+			c.Handle("/conversations.setPurpose", recorded(setConversationPurposeHandler))
 		},
 		func(c slacktest.Customize) {
-			c.Handle("/conversations.rename", renameConversationHandler)
+			c.Handle("/conversations.rename", recorded(renameConversationHandler))
 		},
 		func(c slacktest.Customize) {
-			c.Handle("/conversations.archive", archiveConversationHandler)
+			c.Handle("/conversations.archive", recorded(archiveConversationHandler))
 		},
 		func(c slacktest.Customize) {
-			c.Handle("/conversations.invite", inviteConversationHandler)
+			c.Handle("/conversations.invite", recorded(inviteConversationHandler))
 		},
 		func(c slacktest.Customize) {
-			c.Handle("/users.lookupByEmail", usersLookupByEmailHandler)
+			c.Handle("/users.lookupByEmail", recorded(usersLookupByEmailHandler))
 		},
 		func(c slacktest.Customize) {
-			c.Handle("/conversations.members", getMembersInConversationHandler)
+			c.Handle("/conversations.members", recorded(getMembersInConversationHandler))
 		},
 		func(c slacktest.Customize) {
-			c.Handle("/conversations.kick", kickMemberFromConversationHandler)
+			c.Handle("/conversations.kick", recorded(kickMemberFromConversationHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/conversations.list", recorded(listConversationsHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/users.list", recorded(usersListHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/auth.test", recorded(authTestHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/usergroups.list", recorded(userGroupsListHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/usergroups.create", recorded(createUserGroupHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/usergroups.update", recorded(userGroupHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/usergroups.users.update", recorded(userGroupHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/usergroups.enable", recorded(userGroupHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/usergroups.disable", recorded(userGroupHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/conversations.inviteShared", recorded(inviteSharedHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/admin.roles.addAssignments", recorded(roleAssignmentsHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/admin.roles.removeAssignments", recorded(roleAssignmentsHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/admin.conversations.setConversationPrefs", recorded(setConversationPrefsHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/bookmarks.list", recorded(listBookmarksHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/bookmarks.add", recorded(bookmarkHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/bookmarks.edit", recorded(bookmarkHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/bookmarks.remove", recorded(removeBookmarkHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/chat.postMessage", recorded(postMessageHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/chat.update", recorded(messageHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/chat.delete", recorded(messageHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/pins.list", recorded(listPinsHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/pins.add", recorded(pinHandler))
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/pins.remove", recorded(pinHandler))
 		},
 	)
 
@@ -151,6 +221,226 @@ func kickMemberFromConversationHandler(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(response))
 }
 
+// handle conversations.list
+func listConversationsHandler(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(listConversationsJSON))
+}
+
+// handle auth.test
+func authTestHandler(w http.ResponseWriter, r *http.Request) {
+	token := extractParamValue(r, "token")
+
+	response := ""
+	if token == InvalidToken {
+		response = invalidAuthJSON
+	} else {
+		response = authTestJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle users.list
+func usersListHandler(w http.ResponseWriter, r *http.Request) {
+	cursor := extractParamValue(r, "cursor")
+
+	response := ""
+	if cursor == usersListNextCursor {
+		response = usersListLastPageJSON
+	} else {
+		response = usersListFirstPageJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle usergroups.list
+func userGroupsListHandler(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(userGroupsListJSON))
+}
+
+// handle usergroups.create
+func createUserGroupHandler(w http.ResponseWriter, r *http.Request) {
+	handle := extractParamValue(r, "handle")
+
+	response := ""
+	if handle == UserGroupHandle {
+		response = userGroupNameTakenJSON
+	} else {
+		response = fmt.Sprintf(templateUserGroupResponseJSON, NewUserGroupID, handle)
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle usergroups.update, usergroups.users.update, usergroups.enable and usergroups.disable
+func userGroupHandler(w http.ResponseWriter, r *http.Request) {
+	userGroupID := extractParamValue(r, "usergroup")
+
+	response := ""
+	if userGroupID == NotFoundUserGroupID {
+		response = userGroupNotFoundJSON
+	} else {
+		response = fmt.Sprintf(templateUserGroupResponseJSON, userGroupID, UserGroupHandle)
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle conversations.inviteShared
+func inviteSharedHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel")
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = channelNotFoundJSON
+	} else {
+		response = inviteSharedJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle admin.roles.addAssignments and admin.roles.removeAssignments
+func roleAssignmentsHandler(w http.ResponseWriter, r *http.Request) {
+	entityIDs := extractParamValue(r, "entity_ids")
+
+	response := ""
+	if entityIDs == NotFoundConversationID {
+		response = channelNotFoundJSON
+	} else {
+		response = okJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle admin.conversations.setConversationPrefs
+func setConversationPrefsHandler(w http.ResponseWriter, r *http.Request) {
+	prefs, _ := url.QueryUnescape(extractParamValue(r, "prefs"))
+
+	response := ""
+	if !strings.Contains(prefs, "who_can_post") {
+		response = invalidPrefsJSON
+	} else {
+		response = okJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle bookmarks.list
+func listBookmarksHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel_id")
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = channelNotFoundJSON
+	} else {
+		response = listBookmarksJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle bookmarks.add and bookmarks.edit, which return the bookmark with the given fields
+func bookmarkHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel_id")
+	bookmarkID := extractParamValue(r, "bookmark_id")
+	title, _ := url.QueryUnescape(extractParamValue(r, "title"))
+	link, _ := url.QueryUnescape(extractParamValue(r, "link"))
+	emoji, _ := url.QueryUnescape(extractParamValue(r, "emoji"))
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = channelNotFoundJSON
+	} else if bookmarkID == "" {
+		response = getBookmarkResponse(NewBookmarkID, title, link, emoji)
+	} else if bookmarkID == BookmarkID {
+		response = getBookmarkResponse(bookmarkID, title, link, emoji)
+	} else {
+		response = bookmarkNotFoundJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle bookmarks.remove
+func removeBookmarkHandler(w http.ResponseWriter, r *http.Request) {
+	bookmarkID := extractParamValue(r, "bookmark_id")
+
+	response := ""
+	if bookmarkID != BookmarkID && bookmarkID != NewBookmarkID {
+		response = bookmarkNotFoundJSON
+	} else {
+		response = okJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle chat.postMessage
+func postMessageHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel")
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = channelNotFoundJSON
+	} else {
+		response = getMessageResponse(channelID, NewMessageTimestamp)
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle chat.update and chat.delete
+func messageHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel")
+	timestamp := extractParamValue(r, "ts")
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = channelNotFoundJSON
+	} else if timestamp != PinnedMessageTimestamp && timestamp != NewMessageTimestamp {
+		response = messageNotFoundJSON
+	} else {
+		response = getMessageResponse(channelID, timestamp)
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle pins.list
+func listPinsHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel")
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = channelNotFoundJSON
+	} else {
+		response = getListPinsResponse(channelID)
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle pins.add and pins.remove
+func pinHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel")
+	timestamp := extractParamValue(r, "timestamp")
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = channelNotFoundJSON
+	} else if timestamp != PinnedMessageTimestamp && timestamp != NewMessageTimestamp {
+		response = messageNotFoundJSON
+	} else {
+		response = okJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
 // handle users.lookupByEmail
 func usersLookupByEmailHandler(w http.ResponseWriter, r *http.Request) {
 	email := extractParamValue(r, "email")
@@ -0,0 +1,167 @@
+package slack
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	"golang.org/x/time/rate"
+)
+
+// tier describes the rate limit of a group of slack API methods, see https://api.slack.com/docs/rate-limits
+type tier struct {
+	perMinute int
+	burst     int
+}
+
+var (
+	tier2 = tier{perMinute: 20, burst: 5}
+	tier3 = tier{perMinute: 50, burst: 10}
+	tier4 = tier{perMinute: 100, burst: 20}
+
+	// special methods have their own limits, the highest tier is used for them
+	special = tier{perMinute: 100, burst: 20}
+)
+
+// methodTiers maps the slack API methods used by the operator to their rate limit tier, other methods use tier 3
+var methodTiers = map[string]tier{
+	"admin.conversations.setConversationPrefs": tier2,
+	"admin.roles.addAssignments":               tier2,
+	"admin.roles.removeAssignments":            tier2,
+	"auth.test":                                special,
+	"bookmarks.add":                            tier2,
+	"bookmarks.edit":                           tier2,
+	"bookmarks.list":                           tier3,
+	"bookmarks.remove":                         tier2,
+	"chat.postMessage":                         special,
+	"chat.update":                              tier3,
+	"chat.delete":                              tier3,
+	"conversations.archive":                    tier2,
+	"conversations.create":                     tier2,
+	"conversations.info":                       tier3,
+	"conversations.inviteShared":               tier2,
+	"conversations.invite":                     tier3,
+	"conversations.kick":                       tier3,
+	"conversations.list":                       tier2,
+	"conversations.members":                    tier4,
+	"conversations.rename":                     tier2,
+	"conversations.setPurpose":                 tier2,
+	"conversations.setTopic":                   tier2,
+	"conversations.unarchive":                  tier2,
+	"pins.add":                                 tier2,
+	"pins.list":                                tier2,
+	"pins.remove":                              tier2,
+	"users.info":                               tier4,
+	"users.list":                               tier2,
+	"users.lookupByEmail":                      tier3,
+	"usergroups.create":                        tier2,
+	"usergroups.disable":                       tier2,
+	"usergroups.enable":                        tier2,
+	"usergroups.list":                          tier2,
+	"usergroups.update":                        tier2,
+	"usergroups.users.update":                  tier2,
+}
+
+// maxThrottleWait is the longest a request waits for the client side rate limit, longer waits fail with a
+// slack.RateLimitedError so that the Channel is requeued instead of blocking the reconciler
+const maxThrottleWait = 10 * time.Second
+
+// rateLimitedClient is an http client for the slack API which limits requests per method according to the method's
+// tier and holds back requests to a method after slack responded with Retry-After
+type rateLimitedClient struct {
+	client *http.Client
+
+	mutex      sync.Mutex
+	limiters   map[string]*rate.Limiter
+	retryAfter map[string]time.Time
+}
+
+func newRateLimitedClient() *rateLimitedClient {
+	return &rateLimitedClient{
+		client:     &http.Client{},
+		limiters:   map[string]*rate.Limiter{},
+		retryAfter: map[string]time.Time{},
+	}
+}
+
+// Do sends the request once the rate limit of its method allows it
+func (c *rateLimitedClient) Do(req *http.Request) (*http.Response, error) {
+	method := path.Base(req.URL.Path)
+
+	err := c.wait(req.Context(), method)
+	if err != nil {
+		return nil, err
+	}
+
+	requestsTotal.WithLabelValues(method).Inc()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := strconv.ParseInt(res.Header.Get("Retry-After"), 10, 64)
+		rateLimitedTotal.WithLabelValues(method).Inc()
+
+		c.mutex.Lock()
+		c.retryAfter[method] = time.Now().Add(time.Duration(retryAfter) * time.Second)
+		c.mutex.Unlock()
+	}
+
+	return res, nil
+}
+
+func (c *rateLimitedClient) wait(ctx context.Context, method string) error {
+	c.mutex.Lock()
+	limiter := c.limiter(method)
+	retryAfter := time.Until(c.retryAfter[method])
+	c.mutex.Unlock()
+
+	if retryAfter > 0 {
+		throttledTotal.WithLabelValues(method).Inc()
+		return &slack.RateLimitedError{RetryAfter: retryAfter}
+	}
+
+	reservation := limiter.Reserve()
+	delay := reservation.Delay()
+	if delay > maxThrottleWait {
+		reservation.Cancel()
+		throttledTotal.WithLabelValues(method).Inc()
+		return &slack.RateLimitedError{RetryAfter: delay}
+	}
+
+	throttleWaitSeconds.WithLabelValues(method).Observe(delay.Seconds())
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		reservation.Cancel()
+		return ctx.Err()
+	}
+}
+
+// limiter returns the limiter of the method, it must be called with the mutex held
+func (c *rateLimitedClient) limiter(method string) *rate.Limiter {
+	limiter, ok := c.limiters[method]
+	if !ok {
+		methodTier, ok := methodTiers[method]
+		if !ok {
+			methodTier = tier3
+		}
+		limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(methodTier.perMinute)), methodTier.burst)
+		c.limiters[method] = limiter
+	}
+	return limiter
+}
@@ -0,0 +1,50 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitedClient_shouldReturnRateLimitedError_whenSlackRespondedWithRetryAfter(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	api := slack.New("apitoken", slack.OptionAPIURL(server.URL+"/"), slack.OptionHTTPClient(newRateLimitedClient()))
+
+	_, err := api.GetConversationInfo("C0EAQDV4Z", false)
+	assert.Error(t, err)
+
+	_, err = api.GetConversationInfo("C0EAQDV4Z", false)
+
+	var rateLimitedError *slack.RateLimitedError
+	assert.True(t, errors.As(err, &rateLimitedError))
+	assert.Greater(t, rateLimitedError.RetryAfter.Seconds(), float64(25))
+	assert.Equal(t, 1, requests)
+}
+
+func TestRateLimitedClient_shouldReturnRateLimitedError_whenThrottleWaitIsTooLong(t *testing.T) {
+	client := newRateLimitedClient()
+
+	// Reserve the burst and enough requests beyond it to exceed the maximum throttle wait
+	limiter := client.limiter("conversations.archive")
+	for i := 0; i < tier2.burst+5; i++ {
+		limiter.Reserve()
+	}
+
+	err := client.wait(context.Background(), "conversations.archive")
+
+	var rateLimitedError *slack.RateLimitedError
+	assert.True(t, errors.As(err, &rateLimitedError))
+	assert.True(t, rateLimitedError.RetryAfter > maxThrottleWait)
+}
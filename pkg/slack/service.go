@@ -1,17 +1,35 @@
 package slack
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/slack-go/slack"
 
 	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/config"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
 )
 
 const (
+	TokenNotLoadedError       string = "API token has not been loaded yet"
 	ChannelAlreadyExistsError string = "A channel with the same name already exists"
+	ChannelNotFoundError      string = "Channel with name %s was not found"
+	UserGroupNotFoundError    string = "User group %s was not found"
+	UserGroupIDNotFoundError  string = "User group with ID %s was not found"
+
+	// channelManagerRoleID is the ID of the channel manager role in admin.roles.* methods
+	channelManagerRoleID string = "Rl0A"
 )
 
 // Service interface
@@ -21,36 +39,256 @@ type Service interface {
 	SetTopic(string, string) (*slack.Channel, error)
 	RenameChannel(string, string) (*slack.Channel, error)
 	ArchiveChannel(string) error
-	InviteUsers(string, []string) []error
+	InviteUsers(string, []string) ([]slackv1alpha1.MemberStatus, error)
 	RemoveUsers(string, []string) error
+	GetExtraUsers(string, []string) ([]slack.User, error)
 	GetChannel(string) (*slack.Channel, error)
 	GetUsersInChannel(channelID string) ([]string, error)
 	GetChannelCRFromChannel(*slack.Channel) *slackv1alpha1.Channel
-	IsChannelUpdated(*slackv1alpha1.Channel) (bool, error)
+	GetChannelDrift(*slackv1alpha1.Channel, []string, slackv1alpha1.MembershipPolicy) (*slackv1alpha1.ChannelDrift, error)
+	GetChannelMembers(*slackv1alpha1.Channel) ([]string, error)
 	IsValidChannel(*slackv1alpha1.Channel) error
 	GetChannelByName(string) (*slack.Channel, error)
 	UnArchiveChannel(*slack.Channel) error
+	AuthTest() (*slack.AuthTestResponse, error)
+	TokenError() error
+	CreateUserGroup(string, string, string, []string) (*string, error)
+	UpdateUserGroup(string, string, string, string, []string) error
+	GetUserGroup(string) (*slack.UserGroup, error)
+	GetUserGroupByHandle(string) (*slack.UserGroup, error)
+	SetUserGroupMembers(*slack.UserGroup, []string) []error
+	EnableUserGroup(string) error
+	DisableUserGroup(string) error
+	AddChannelManagers(string, []string) error
+	RemoveChannelManagers(string, []string) error
+	SetPostingPolicy(string, slackv1alpha1.PostingPolicy, []string) error
+	InviteShared(string, string, bool) (string, error)
+	GetExternalMembers(string) ([]slack.User, error)
+	ListBookmarks(string) ([]Bookmark, error)
+	AddBookmark(string, Bookmark) (*Bookmark, error)
+	EditBookmark(string, Bookmark) (*Bookmark, error)
+	RemoveBookmark(string, string) error
+	PostMessage(string, string, string) (string, error)
+	ReplyToMessage(string, string, string, string) (string, error)
+	UpdateMessage(string, string, string, string) error
+	DeleteMessage(string, string) error
+	ListPinnedMessages(string) (map[string]string, error)
+	PinMessage(string, string) error
+	UnpinMessage(string, string) error
+	InvalidateUsers(string)
 }
 
 // SlackService structure
 type SlackService struct {
-	log logr.Logger
-	api *slack.Client
+	log        logr.Logger
+	apiURL     string
+	httpClient *rateLimitedClient
+	users      *userDirectory
+
+	// api holds the *connection, which is swapped when the API token is rotated
+	api atomic.Value
+
+	// tokenMutex serializes token updates and guards tokenErr and rejectedErr. tokenErr is set while the service has
+	// no valid token, rejectedErr while the last token it was given was rejected and the previous one remains in use
+	tokenMutex  sync.Mutex
+	tokenErr    error
+	rejectedErr error
 }
 
-// New creates a new SlackService
+// New creates a new SlackService, an empty token leaves the service degraded until SetToken is called
 func New(APIToken string, logger logr.Logger) *SlackService {
-	return &SlackService{
-		api: slack.New(APIToken),
-		log: logger,
+	return newService(APIToken, config.Get().UserCacheTTL.Duration, logger, slack.APIURL)
+}
+
+func newService(APIToken string, userCacheTTL time.Duration, logger logr.Logger, apiURL string) *SlackService {
+	s := &SlackService{
+		log:        logger,
+		apiURL:     apiURL,
+		httpClient: newRateLimitedClient(),
+	}
+	s.api.Store(s.newConnection(APIToken))
+	if APIToken == "" {
+		s.tokenErr = fmt.Errorf(TokenNotLoadedError)
+	}
+	s.users = newUserDirectory(userCacheTTL, func() ([]slack.User, error) { return listAllUsers(s.client()) })
+
+	return s
+}
+
+// connection is a slack client together with its API token, which is needed for the methods the client lacks
+type connection struct {
+	client *slack.Client
+	token  string
+
+	// teamMutex guards teamID, the ID of the workspace of the token which is looked up when first needed
+	teamMutex sync.Mutex
+	teamID    string
+}
+
+func (s *SlackService) newConnection(APIToken string) *connection {
+	return &connection{
+		client: slack.New(APIToken, slack.OptionAPIURL(s.apiURL), slack.OptionHTTPClient(s.httpClient)),
+		token:  APIToken,
+	}
+}
+
+// client returns the slack client for the current API token
+func (s *SlackService) client() *slack.Client {
+	return s.api.Load().(*connection).client
+}
+
+// teamID returns the ID of the workspace of the current API token
+func (s *SlackService) teamID() (string, error) {
+	api := s.api.Load().(*connection)
+
+	api.teamMutex.Lock()
+	defer api.teamMutex.Unlock()
+
+	if api.teamID == "" {
+		response, err := api.client.AuthTest()
+		if err != nil {
+			return "", err
+		}
+		api.teamID = response.TeamID
+	}
+	return api.teamID, nil
+}
+
+// isExternal returns true if the user belongs to another organization, e.g. a member of a shared channel
+func (s *SlackService) isExternal(user *slack.User) (bool, error) {
+	if user.IsStranger {
+		return true, nil
+	}
+	if user.TeamID == "" {
+		return false, nil
+	}
+
+	teamID, err := s.teamID()
+	if err != nil {
+		return false, err
+	}
+	return user.TeamID != teamID, nil
+}
+
+// SetToken validates the API token with auth.test and swaps the slack client to use it. An invalid token is
+// rejected and reported by CheckToken, the previous token remains in use. Without a previous token it is reported by
+// TokenError as well.
+func (s *SlackService) SetToken(APIToken string) error {
+	s.tokenMutex.Lock()
+	defer s.tokenMutex.Unlock()
+
+	api := s.newConnection(APIToken)
+
+	response, err := api.client.AuthTest()
+	if err != nil {
+		s.log.Error(err, "Rejecting invalid API token")
+		err = fmt.Errorf("API token is invalid: %s", err.Error())
+		if s.tokenErr != nil {
+			s.tokenErr = err
+		} else {
+			s.rejectedErr = err
+		}
+		return err
+	}
+
+	s.log.Info("Using API token", "team", response.Team, "teamID", response.TeamID)
+	api.teamID = response.TeamID
+	s.api.Store(api)
+	s.tokenErr = nil
+	s.rejectedErr = nil
+
+	// The token may belong to another workspace
+	s.users.Invalidate()
+
+	return nil
+}
+
+// TokenError returns why the service has no valid API token, or nil if it has one
+func (s *SlackService) TokenError() error {
+	s.tokenMutex.Lock()
+	defer s.tokenMutex.Unlock()
+
+	return s.tokenErr
+}
+
+// CheckToken is a readiness check which fails while the service has no valid API token, or while the last token it
+// was given was rejected
+func (s *SlackService) CheckToken(_ *http.Request) error {
+	s.tokenMutex.Lock()
+	defer s.tokenMutex.Unlock()
+
+	if s.tokenErr != nil {
+		return s.tokenErr
+	}
+	return s.rejectedErr
+}
+
+// InvalidateUsers expires the cached users if the token belongs to the workspace with the ID, e.g. after a user joined
+// or changed. They are expired as well while the workspace of the token has not been looked up yet.
+func (s *SlackService) InvalidateUsers(teamID string) {
+	api := s.api.Load().(*connection)
+
+	api.teamMutex.Lock()
+	tokenTeamID := api.teamID
+	api.teamMutex.Unlock()
+
+	if teamID == "" || tokenTeamID == "" || tokenTeamID == teamID {
+		s.users.Invalidate()
 	}
 }
 
+// getUserByEmail returns the user with the given email from the user directory, users that joined after the
+// directory was loaded are looked up individually. Emails without a user are not looked up again until the directory
+// is reloaded.
+func (s *SlackService) getUserByEmail(email string) (*slack.User, error) {
+	user, found, err := s.users.GetByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return &user, nil
+	}
+	if s.users.IsNotFound(email) {
+		return nil, errors.New(usersNotFoundError)
+	}
+
+	newUser, err := s.client().GetUserByEmail(email)
+	if err != nil {
+		if err.Error() == usersNotFoundError {
+			s.users.AddNotFound(email)
+		}
+		return nil, err
+	}
+	s.users.Add(*newUser)
+
+	return newUser, nil
+}
+
+// getUserByID returns the user with the given ID from the user directory, users that joined after the
+// directory was loaded are looked up individually
+func (s *SlackService) getUserByID(userID string) (*slack.User, error) {
+	user, found, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return &user, nil
+	}
+
+	newUser, err := s.client().GetUserInfo(userID)
+	if err != nil {
+		return nil, err
+	}
+	s.users.Add(*newUser)
+
+	return newUser, nil
+}
+
 // GetChannel gets a channel on slack
 func (s *SlackService) GetChannel(channelID string) (*slack.Channel, error) {
 	log := s.log.WithValues("channelID", channelID)
 
-	channel, err := s.api.GetConversationInfo(channelID, false)
+	channel, err := s.client().GetConversationInfo(channelID, false)
 	if err != nil {
 		log.Error(err, "Error fetching channel")
 		return nil, err
@@ -63,7 +301,7 @@ func (s *SlackService) GetChannel(channelID string) (*slack.Channel, error) {
 func (s *SlackService) CreateChannel(name string, isPrivate bool) (*string, error) {
 	s.log.Info("Creating Slack Channel", "name", name, "isPrivate", isPrivate)
 
-	channel, err := s.api.CreateConversation(name, isPrivate)
+	channel, err := s.client().CreateConversation(name, isPrivate)
 	if err != nil {
 		return nil, err
 	}
@@ -77,20 +315,9 @@ func (s *SlackService) CreateChannel(name string, isPrivate bool) (*string, erro
 func (s *SlackService) SetDescription(channelID string, description string) (*slack.Channel, error) {
 	log := s.log.WithValues("channelID", channelID)
 
-	channel, err := s.api.GetConversationInfo(channelID, false)
-
-	if err != nil {
-		log.Error(err, "Error fetching channel")
-		return nil, err
-	}
-
-	if html.UnescapeString(channel.Purpose.Value) == description {
-		return channel, nil
-	}
-
 	log.V(1).Info("Setting Description of the Slack Channel")
 
-	channel, err = s.api.SetPurposeOfConversation(channelID, description)
+	channel, err := s.client().SetPurposeOfConversation(channelID, description)
 
 	if err != nil {
 		log.Error(err, "Error setting description of the channel")
@@ -103,20 +330,9 @@ func (s *SlackService) SetDescription(channelID string, description string) (*sl
 func (s *SlackService) SetTopic(channelID string, topic string) (*slack.Channel, error) {
 	log := s.log.WithValues("channelID", channelID)
 
-	channel, err := s.api.GetConversationInfo(channelID, false)
-
-	if err != nil {
-		log.Error(err, "Error fetching channel")
-		return nil, err
-	}
-
-	if html.UnescapeString(channel.Topic.Value) == topic {
-		return channel, nil
-	}
-
 	log.V(1).Info("Setting Topic of the Slack Channel")
 
-	channel, err = s.api.SetTopicOfConversation(channelID, topic)
+	channel, err := s.client().SetTopicOfConversation(channelID, topic)
 
 	if err != nil {
 		log.Error(err, "Error setting topic of the channel")
@@ -129,19 +345,9 @@ func (s *SlackService) SetTopic(channelID string, topic string) (*slack.Channel,
 func (s *SlackService) RenameChannel(channelID string, newName string) (*slack.Channel, error) {
 	log := s.log.WithValues("channelID", channelID)
 
-	channel, err := s.api.GetConversationInfo(channelID, false)
-
-	if err != nil {
-		log.Error(err, "Error fetching channel")
-		return nil, err
-	}
-	if html.UnescapeString(channel.Name) == newName {
-		return channel, nil
-	}
-
 	log.V(1).Info("Renaming Slack Channel", "newName", newName)
 
-	channel, err = s.api.RenameConversation(channelID, newName)
+	channel, err := s.client().RenameConversation(channelID, newName)
 
 	if err != nil {
 		log.Error(err, "Error renaming channel")
@@ -155,7 +361,7 @@ func (s *SlackService) ArchiveChannel(channelID string) error {
 	log := s.log.WithValues("channelID", channelID)
 
 	log.V(1).Info("Archiving channel")
-	err := s.api.ArchiveConversation(channelID)
+	err := s.client().ArchiveConversation(channelID)
 
 	if err != nil {
 		log.Error(err, "Error archiving channel")
@@ -167,7 +373,7 @@ func (s *SlackService) ArchiveChannel(channelID string) error {
 
 // GetUsersInChannel get all the users in the slack channel
 func (s *SlackService) GetUsersInChannel(channelID string) ([]string, error) {
-	userIDs, _, err := s.api.GetUsersInConversation(&slack.GetUsersInConversationParameters{
+	userIDs, _, err := s.client().GetUsersInConversation(&slack.GetUsersInConversationParameters{
 		ChannelID: channelID,
 		Limit:     100000,
 	})
@@ -175,69 +381,131 @@ func (s *SlackService) GetUsersInChannel(channelID string) ([]string, error) {
 	return userIDs, err
 }
 
-// InviteUsers invites users to the slack channel
-func (s *SlackService) InviteUsers(channelID string, userEmails []string) []error {
+// InviteUsers invites the users that are not in the slack channel yet and returns the membership status of every
+// user. Users that can't be invited don't keep the others from being invited, only rate limits stop the invites.
+func (s *SlackService) InviteUsers(channelID string, userEmails []string) ([]slackv1alpha1.MemberStatus, error) {
 	log := s.log.WithValues("channelID", channelID)
 
-	var errorlist []error
+	channelUserIDs, err := s.GetUsersInChannel(channelID)
+	if err != nil {
+		log.Error(err, "Error getting users in a conversation")
+		return nil, err
+	}
+
+	inChannel := map[string]bool{}
+	for _, userID := range channelUserIDs {
+		inChannel[userID] = true
+	}
+
+	var members []slackv1alpha1.MemberStatus
 
 	for _, email := range userEmails {
-		user, err := s.api.GetUserByEmail(email)
+		member := slackv1alpha1.MemberStatus{Email: email, State: slackv1alpha1.JoinedMemberState}
 
-		if err != nil {
-			errorlist = append(errorlist, fmt.Errorf(fmt.Sprintf("Error fetching user by Email %s", email)))
+		user, err := s.getUserByEmail(email)
+		if err != nil && err.Error() == usersNotFoundError {
+			member.State = slackv1alpha1.NotFoundMemberState
+			member.LastError = fmt.Sprintf("Error fetching user by Email %s", email)
+			members = append(members, member)
 			continue
 		}
+		if err != nil {
+			log.Error(err, fmt.Sprintf("Error fetching user by Email %s", email))
+			return nil, err
+		}
 
-		log.V(1).Info("Inviting user to Slack Channel", "userID", user.ID)
-		_, err = s.api.InviteUsersToConversation(channelID, user.ID)
+		member.UserID = user.ID
 
-		if err != nil && err.Error() != "already_in_channel" {
-			log.Error(err, "Error Inviting user to channel", "userID", user.ID)
-			errorlist = append(errorlist, err)
+		if user.Deleted {
+			member.State = slackv1alpha1.DeactivatedMemberState
+			member.LastError = "User is deactivated"
+		} else if !inChannel[user.ID] {
+			log.V(1).Info("Inviting user to Slack Channel", "userID", user.ID)
+			_, err = s.client().InviteUsersToConversation(channelID, user.ID)
+
+			var rateLimitedError *slack.RateLimitedError
+			if errors.As(err, &rateLimitedError) {
+				return nil, err
+			}
+			if err != nil && err.Error() != "already_in_channel" {
+				log.Error(err, "Error Inviting user to channel", "userID", user.ID)
+				member.State = slackv1alpha1.InviteFailedMemberState
+				member.LastError = err.Error()
+			}
 		}
+
+		members = append(members, member)
 	}
 
-	return errorlist
+	return members, nil
 }
 
 // RemoveUsers remove users from the slack channel
 func (s *SlackService) RemoveUsers(channelID string, userEmails []string) error {
 	log := s.log.WithValues("channelID", channelID)
 
+	extraUsers, err := s.GetExtraUsers(channelID, userEmails)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range extraUsers {
+		err = s.client().KickUserFromConversation(channelID, user.ID)
+		if err != nil {
+			log.Error(err, "Error removing user from the conversation")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetExtraUsers returns the users in the slack channel, excluding bots, whose emails are not in the given list
+func (s *SlackService) GetExtraUsers(channelID string, userEmails []string) ([]slack.User, error) {
+	log := s.log.WithValues("channelID", channelID)
+
 	channelUserIDs, err := s.GetUsersInChannel(channelID)
 	if err != nil {
 		log.Error(err, "Error getting users in a conversation")
-		return err
+		return nil, err
 	}
 
+	return s.filterExtraUsers(channelUserIDs, userEmails)
+}
+
+func (s *SlackService) filterExtraUsers(channelUserIDs []string, userEmails []string) ([]slack.User, error) {
+	var extraUsers []slack.User
+
 	for _, userId := range channelUserIDs {
-		user, err := s.api.GetUserInfo(userId)
+		user, err := s.getUserByID(userId)
 		if err != nil {
-			log.Error(err, "Error fetching user info")
-			return err
+			s.log.Error(err, "Error fetching user info")
+			return nil, err
+		}
+
+		external, err := s.isExternal(user)
+		if err != nil {
+			s.log.Error(err, "Error fetching workspace of the API token")
+			return nil, err
 		}
 
-		if !user.IsBot {
+		// Bots and deactivated users can't be removed from the channel, external members belong to shared channels
+		if !user.IsBot && !user.Deleted && !external {
 			found := false
 			for _, email := range userEmails {
-				if email == user.Profile.Email {
+				if strings.EqualFold(email, user.Profile.Email) {
 					found = true
 					break
 				}
 			}
 
 			if !found {
-				err = s.api.KickUserFromConversation(channelID, user.ID)
-				if err != nil {
-					log.Error(err, "Error removing user from the conversation")
-					return err
-				}
+				extraUsers = append(extraUsers, *user)
 			}
 		}
 	}
 
-	return nil
+	return extraUsers, nil
 }
 
 func (s *SlackService) GetChannelCRFromChannel(existingChannel *slack.Channel) *slackv1alpha1.Channel {
@@ -252,43 +520,65 @@ func (s *SlackService) GetChannelCRFromChannel(existingChannel *slack.Channel) *
 	return &channel
 }
 
-func (s *SlackService) IsChannelUpdated(channel *slackv1alpha1.Channel) (bool, error) {
+// GetChannelDrift returns the differences between the Channel spec and the slack channel, or nil if there are none.
+// userEmails are the emails of the members the channel should have, the membership policy decides whether missing and
+// extra members are part of the drift.
+func (s *SlackService) GetChannelDrift(channel *slackv1alpha1.Channel, userEmails []string, membershipPolicy slackv1alpha1.MembershipPolicy) (*slackv1alpha1.ChannelDrift, error) {
 	log := s.log.WithValues("channelID", channel.Status.ID)
 
 	channelID := channel.Status.ID
 	name := channel.Spec.Name
 	topic := channel.Spec.Topic
 	description := channel.Spec.Description
-	userEmails := channel.Spec.Users
 
-	existingChannel, err := s.api.GetConversationInfo(channel.Status.ID, false)
+	existingChannel, err := s.client().GetConversationInfo(channelID, false)
 	if err != nil {
 		log.Error(err, "Error fetching channel")
-		return false, err
+		return nil, err
 	}
 
-	if html.UnescapeString(existingChannel.Name) != name {
-		return true, nil
+	drift := &slackv1alpha1.ChannelDrift{}
+	driftFound := false
+
+	if existingName := html.UnescapeString(existingChannel.Name); existingName != name {
+		drift.Name = &slackv1alpha1.FieldDrift{Desired: name, Actual: existingName}
+		driftFound = true
 	}
-	if html.UnescapeString(existingChannel.Topic.Value) != topic {
-		return true, nil
+	if existingTopic := html.UnescapeString(existingChannel.Topic.Value); existingTopic != topic {
+		drift.Topic = &slackv1alpha1.FieldDrift{Desired: topic, Actual: existingTopic}
+		driftFound = true
 	}
-	if html.UnescapeString(existingChannel.Purpose.Value) != description {
-		return true, nil
+	if existingDescription := html.UnescapeString(existingChannel.Purpose.Value); existingDescription != description {
+		drift.Description = &slackv1alpha1.FieldDrift{Desired: description, Actual: existingDescription}
+		driftFound = true
+	}
+
+	if membershipPolicy == slackv1alpha1.IgnoreMembershipPolicy {
+		if !driftFound {
+			return nil, nil
+		}
+		return drift, nil
 	}
 
 	channelUserIDs, err := s.GetUsersInChannel(channelID)
 	if err != nil {
 		log.Error(err, "Error getting users in a conversation")
-		return false, err
+		return nil, err
 	}
 
-	// Checking if the user is added
+	// Checking if the user is added, unknown and deactivated users can't be invited and are already reported in the
+	// members of the status
 	for _, email := range userEmails {
-		user, err := s.api.GetUserByEmail(email)
+		user, err := s.getUserByEmail(email)
+		if err != nil && err.Error() == usersNotFoundError {
+			continue
+		}
 		if err != nil {
 			log.Error(err, fmt.Sprintf("Error fetching user by Email %s", email))
-			return false, err
+			return nil, err
+		}
+		if user.Deleted {
+			continue
 		}
 
 		found := false
@@ -300,39 +590,97 @@ func (s *SlackService) IsChannelUpdated(channel *slackv1alpha1.Channel) (bool, e
 		}
 
 		if !found {
-			return true, nil
+			drift.MissingUsers = append(drift.MissingUsers, email)
+			driftFound = true
 		}
 	}
 
-	// Checking if the user is removed
-	for _, userId := range channelUserIDs {
-		user, err := s.api.GetUserInfo(userId)
+	// Checking if the user is removed, members are only removed by the authoritative policy
+	if membershipPolicy == slackv1alpha1.AuthoritativeMembershipPolicy {
+		extraUsers, err := s.filterExtraUsers(channelUserIDs, userEmails)
 		if err != nil {
-			log.Error(err, "Error fetching user info")
-			return false, err
+			return nil, err
 		}
 
-		if !user.IsBot {
-			found := false
-			for _, email := range userEmails {
-				if email == user.Profile.Email {
-					found = true
-					break
-				}
+		for _, user := range extraUsers {
+			drift.ExtraUsers = append(drift.ExtraUsers, user.Profile.Email)
+			driftFound = true
+		}
+	}
+
+	if !driftFound {
+		return nil, nil
+	}
+	return drift, nil
+}
+
+// GetChannelMembers returns the emails of the users of the Channel and the members of its user groups. The user
+// groups are looked up on every call, so that the members follow changes made to the user groups on slack.
+func (s *SlackService) GetChannelMembers(channel *slackv1alpha1.Channel) ([]string, error) {
+	var members []string
+	seen := map[string]bool{}
+
+	addMember := func(email string) {
+		if !seen[strings.ToLower(email)] {
+			seen[strings.ToLower(email)] = true
+			members = append(members, email)
+		}
+	}
+
+	for _, email := range channel.Spec.Users {
+		addMember(email)
+	}
+
+	if len(channel.Spec.UserGroups) == 0 {
+		return members, nil
+	}
+
+	userGroups, err := s.client().GetUserGroups(slack.GetUserGroupsOptionIncludeUsers(true))
+	if err != nil {
+		s.log.Error(err, "Error fetching user groups")
+		return nil, err
+	}
+
+	for _, handleOrID := range channel.Spec.UserGroups {
+		userGroup := findUserGroup(userGroups, handleOrID)
+		if userGroup == nil {
+			return nil, fmt.Errorf(UserGroupNotFoundError, handleOrID)
+		}
+
+		for _, userID := range userGroup.Users {
+			user, err := s.getUserByID(userID)
+			if err != nil {
+				s.log.Error(err, "Error fetching user info", "userID", userID)
+				return nil, err
 			}
 
-			if !found {
-				return true, nil
+			// Bots and deactivated users can't be invited to the channel
+			if user.IsBot || user.Deleted || user.Profile.Email == "" {
+				continue
 			}
+			addMember(user.Profile.Email)
 		}
 	}
 
-	return false, nil
+	return members, nil
+}
+
+// findUserGroup returns the user group with the given ID or handle, the handle may be prefixed with an @
+func findUserGroup(userGroups []slack.UserGroup, handleOrID string) *slack.UserGroup {
+	handle := strings.TrimPrefix(handleOrID, "@")
+
+	for i := range userGroups {
+		if userGroups[i].ID == handleOrID || userGroups[i].Handle == handle {
+			return &userGroups[i]
+		}
+	}
+	return nil
 }
 
 func (s *SlackService) IsValidChannel(channel *slackv1alpha1.Channel) error {
-	if len(channel.Spec.Users) < 1 {
-		return fmt.Errorf("Users can not be empty")
+	if len(channel.Spec.Users) < 1 && len(channel.Spec.UserGroups) < 1 && channel.Spec.MembersFrom == nil && len(channel.Spec.Managers) < 1 &&
+		channel.Spec.MembershipPolicy != slackv1alpha1.IgnoreMembershipPolicy {
+		return fmt.Errorf("Users, UserGroups, MembersFrom and Managers can not all be empty unless the membership policy is Ignore")
 	}
 
 	return nil
@@ -343,7 +691,7 @@ func (s *SlackService) GetChannelByName(name string) (*slack.Channel, error) {
 	var cursor string
 
 	for {
-		channels, nextCursor, err := s.api.GetConversations(&slack.GetConversationsParameters{
+		channels, nextCursor, err := s.client().GetConversations(&slack.GetConversationsParameters{
 			Types: []string{
 				"private_channel",
 				"public_channel",
@@ -368,13 +716,482 @@ func (s *SlackService) GetChannelByName(name string) (*slack.Channel, error) {
 		cursor = nextCursor
 	}
 
-	return nil, fmt.Errorf(ChannelAlreadyExistsError)
+	return nil, fmt.Errorf(ChannelNotFoundError, name)
 }
 
 // UnArchiveChannel unarchives the channel
 func (s *SlackService) UnArchiveChannel(channel *slack.Channel) error {
-	err := s.api.UnArchiveConversation(channel.ID)
+	err := s.client().UnArchiveConversation(channel.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// AuthTest checks the API token and returns the workspace it belongs to
+func (s *SlackService) AuthTest() (*slack.AuthTestResponse, error) {
+	response, err := s.client().AuthTest()
 	if err != nil {
+		s.log.Error(err, "Error testing API token")
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// CreateUserGroup creates a user group on slack with the given handle, name, description and default channels
+func (s *SlackService) CreateUserGroup(handle string, name string, description string, channelIDs []string) (*string, error) {
+	s.log.Info("Creating Slack User Group", "handle", handle)
+
+	userGroup, err := s.client().CreateUserGroup(slack.UserGroup{
+		Handle:      handle,
+		Name:        name,
+		Description: description,
+		Prefs:       slack.UserGroupPrefs{Channels: channelIDs},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.V(1).Info("Created Slack User Group", "userGroup", userGroup)
+
+	return &userGroup.ID, nil
+}
+
+// UpdateUserGroup sets the handle, name, description and default channels of the user group
+func (s *SlackService) UpdateUserGroup(userGroupID string, handle string, name string, description string, channelIDs []string) error {
+	log := s.log.WithValues("userGroupID", userGroupID)
+
+	log.V(1).Info("Updating Slack User Group")
+
+	_, err := s.client().UpdateUserGroup(slack.UserGroup{
+		ID:          userGroupID,
+		Handle:      handle,
+		Name:        name,
+		Description: description,
+		Prefs:       slack.UserGroupPrefs{Channels: channelIDs},
+	})
+	if err != nil {
+		log.Error(err, "Error updating user group")
+		return err
+	}
+	return nil
+}
+
+// GetUserGroup gets a user group on slack, including its members
+func (s *SlackService) GetUserGroup(userGroupID string) (*slack.UserGroup, error) {
+	userGroups, err := s.listUserGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range userGroups {
+		if userGroups[i].ID == userGroupID {
+			return &userGroups[i], nil
+		}
+	}
+	return nil, fmt.Errorf(UserGroupIDNotFoundError, userGroupID)
+}
+
+// GetUserGroupByHandle search for the user group on slack by handle, including disabled user groups
+func (s *SlackService) GetUserGroupByHandle(handle string) (*slack.UserGroup, error) {
+	userGroups, err := s.listUserGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range userGroups {
+		if userGroups[i].Handle == handle {
+			return &userGroups[i], nil
+		}
+	}
+	return nil, fmt.Errorf(UserGroupNotFoundError, handle)
+}
+
+func (s *SlackService) listUserGroups() ([]slack.UserGroup, error) {
+	userGroups, err := s.client().GetUserGroups(slack.GetUserGroupsOptionIncludeUsers(true), slack.GetUserGroupsOptionIncludeDisabled(true))
+	if err != nil {
+		s.log.Error(err, "Error fetching user groups")
+		return nil, err
+	}
+	return userGroups, nil
+}
+
+// SetUserGroupMembers replaces the members of the user group with the users with the given emails, if they differ.
+// Users that can't be found are reported and left out.
+func (s *SlackService) SetUserGroupMembers(userGroup *slack.UserGroup, userEmails []string) []error {
+	log := s.log.WithValues("userGroupID", userGroup.ID)
+
+	var errorlist []error
+	var userIDs []string
+
+	for _, email := range userEmails {
+		user, err := s.getUserByEmail(email)
+		if err != nil {
+			errorlist = append(errorlist, fmt.Errorf(fmt.Sprintf("Error fetching user by Email %s", email)))
+			continue
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+
+	// A user group can't be left without members
+	if len(userIDs) == 0 || pkgutil.SameElements(userIDs, userGroup.Users) {
+		return errorlist
+	}
+
+	log.V(1).Info("Setting members of Slack User Group", "userIDs", userIDs)
+	_, err := s.client().UpdateUserGroupMembers(userGroup.ID, strings.Join(userIDs, ","))
+	if err != nil {
+		log.Error(err, "Error setting members of user group")
+		errorlist = append(errorlist, err)
+	}
+
+	return errorlist
+}
+
+// EnableUserGroup enables a disabled user group
+func (s *SlackService) EnableUserGroup(userGroupID string) error {
+	_, err := s.client().EnableUserGroup(userGroupID)
+	if err != nil {
+		s.log.Error(err, "Error enabling user group", "userGroupID", userGroupID)
+		return err
+	}
+	return nil
+}
+
+// DisableUserGroup disables the user group, slack doesn't allow deleting user groups
+func (s *SlackService) DisableUserGroup(userGroupID string) error {
+	_, err := s.client().DisableUserGroup(userGroupID)
+	if err != nil {
+		s.log.Error(err, "Error disabling user group", "userGroupID", userGroupID)
+		return err
+	}
+	return nil
+}
+
+// AddChannelManagers assigns the channel manager role of the channel to the users, which requires an admin token
+func (s *SlackService) AddChannelManagers(channelID string, userEmails []string) error {
+	return s.assignChannelManagers("admin.roles.addAssignments", channelID, userEmails)
+}
+
+// RemoveChannelManagers removes the channel manager role of the channel from the users, which requires an admin token
+func (s *SlackService) RemoveChannelManagers(channelID string, userEmails []string) error {
+	return s.assignChannelManagers("admin.roles.removeAssignments", channelID, userEmails)
+}
+
+func (s *SlackService) assignChannelManagers(method string, channelID string, userEmails []string) error {
+	log := s.log.WithValues("channelID", channelID, "method", method)
+
+	userIDs, err := s.getUserIDs(userEmails)
+	if err != nil {
+		return err
+	}
+
+	log.V(1).Info("Assigning channel managers", "userIDs", userIDs)
+	err = s.callAPI(method, url.Values{
+		"role_id":    {channelManagerRoleID},
+		"entity_ids": {channelID},
+		"user_ids":   {strings.Join(userIDs, ",")},
+	}, nil)
+	if err != nil {
+		log.Error(err, "Error assigning channel managers")
+		return err
+	}
+	return nil
+}
+
+// SetPostingPolicy restricts who can post in the channel, which requires an admin token. The channel managers are
+// allowed to post by the Managers policy.
+func (s *SlackService) SetPostingPolicy(channelID string, policy slackv1alpha1.PostingPolicy, managerEmails []string) error {
+	log := s.log.WithValues("channelID", channelID)
+
+	var whoCanPost []string
+	switch policy {
+	case slackv1alpha1.MembersPostingPolicy:
+		whoCanPost = []string{"type:admin", "type:regular"}
+	case slackv1alpha1.AdminsPostingPolicy:
+		whoCanPost = []string{"type:admin"}
+	case slackv1alpha1.ManagersPostingPolicy:
+		managerIDs, err := s.getUserIDs(managerEmails)
+		if err != nil {
+			return err
+		}
+		whoCanPost = []string{"type:admin"}
+		for _, managerID := range managerIDs {
+			whoCanPost = append(whoCanPost, "user:"+managerID)
+		}
+	default:
+		return fmt.Errorf("Unknown posting policy %s", policy)
+	}
+
+	prefs, err := json.Marshal(map[string]string{"who_can_post": strings.Join(whoCanPost, ",")})
+	if err != nil {
+		return err
+	}
+
+	log.V(1).Info("Setting posting policy", "policy", policy, "prefs", string(prefs))
+	err = s.callAPI("admin.conversations.setConversationPrefs", url.Values{
+		"channel_id": {channelID},
+		"prefs":      {string(prefs)},
+	}, nil)
+	if err != nil {
+		log.Error(err, "Error setting posting policy")
+		return err
+	}
+	return nil
+}
+
+// getUserIDs returns the IDs of the users with the given emails
+func (s *SlackService) getUserIDs(userEmails []string) ([]string, error) {
+	var userIDs []string
+	for _, email := range userEmails {
+		user, err := s.getUserByEmail(email)
+		if err != nil {
+			s.log.Error(err, "Error fetching user by Email", "email", email)
+			return nil, fmt.Errorf(fmt.Sprintf("Error fetching user by Email %s", email))
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	return userIDs, nil
+}
+
+// inviteSharedResponse is the response of conversations.inviteShared
+type inviteSharedResponse struct {
+	slack.SlackResponse
+	InviteID string `json:"invite_id"`
+}
+
+// InviteShared sends a Slack Connect invitation to share the channel with the organization of the email and returns
+// the ID of the invitation. An external limited invitation doesn't let the invited organization manage the channel.
+func (s *SlackService) InviteShared(channelID string, email string, externalLimited bool) (string, error) {
+	log := s.log.WithValues("channelID", channelID)
+
+	log.Info("Inviting external organization to Slack Channel", "email", email)
+	response := &inviteSharedResponse{}
+	err := s.callAPI("conversations.inviteShared", url.Values{
+		"channel":          {channelID},
+		"emails":           {email},
+		"external_limited": {strconv.FormatBool(externalLimited)},
+	}, response)
+	if err != nil {
+		log.Error(err, "Error inviting external organization to channel", "email", email)
+		return "", err
+	}
+
+	return response.InviteID, nil
+}
+
+// GetExternalMembers returns the members of the slack channel which belong to other organizations
+func (s *SlackService) GetExternalMembers(channelID string) ([]slack.User, error) {
+	channelUserIDs, err := s.GetUsersInChannel(channelID)
+	if err != nil {
+		s.log.Error(err, "Error getting users in a conversation", "channelID", channelID)
+		return nil, err
+	}
+
+	var externalMembers []slack.User
+	for _, userID := range channelUserIDs {
+		user, err := s.getUserByID(userID)
+		if err != nil {
+			s.log.Error(err, "Error fetching user info")
+			return nil, err
+		}
+
+		external, err := s.isExternal(user)
+		if err != nil {
+			return nil, err
+		}
+		if external {
+			externalMembers = append(externalMembers, *user)
+		}
+	}
+
+	return externalMembers, nil
+}
+
+// Bookmark is a link bookmarked in a slack channel
+type Bookmark struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Link  string `json:"link"`
+	Emoji string `json:"emoji"`
+}
+
+// bookmarkResponse is the response of bookmarks.add and bookmarks.edit
+type bookmarkResponse struct {
+	slack.SlackResponse
+	Bookmark Bookmark `json:"bookmark"`
+}
+
+// listBookmarksResponse is the response of bookmarks.list
+type listBookmarksResponse struct {
+	slack.SlackResponse
+	Bookmarks []Bookmark `json:"bookmarks"`
+}
+
+// ListBookmarks returns the bookmarks of the slack channel
+func (s *SlackService) ListBookmarks(channelID string) ([]Bookmark, error) {
+	response := &listBookmarksResponse{}
+	err := s.callAPI("bookmarks.list", url.Values{"channel_id": {channelID}}, response)
+	if err != nil {
+		s.log.Error(err, "Error listing bookmarks", "channelID", channelID)
+		return nil, err
+	}
+	return response.Bookmarks, nil
+}
+
+// AddBookmark bookmarks a link in the slack channel
+func (s *SlackService) AddBookmark(channelID string, bookmark Bookmark) (*Bookmark, error) {
+	response := &bookmarkResponse{}
+	err := s.callAPI("bookmarks.add", url.Values{
+		"channel_id": {channelID},
+		"type":       {"link"},
+		"title":      {bookmark.Title},
+		"link":       {bookmark.Link},
+		"emoji":      {bookmark.Emoji},
+	}, response)
+	if err != nil {
+		s.log.Error(err, "Error adding bookmark", "channelID", channelID, "title", bookmark.Title)
+		return nil, err
+	}
+	return &response.Bookmark, nil
+}
+
+// EditBookmark changes the title, link and emoji of the bookmark with the ID of the given bookmark
+func (s *SlackService) EditBookmark(channelID string, bookmark Bookmark) (*Bookmark, error) {
+	response := &bookmarkResponse{}
+	err := s.callAPI("bookmarks.edit", url.Values{
+		"channel_id":  {channelID},
+		"bookmark_id": {bookmark.ID},
+		"title":       {bookmark.Title},
+		"link":        {bookmark.Link},
+		"emoji":       {bookmark.Emoji},
+	}, response)
+	if err != nil {
+		s.log.Error(err, "Error editing bookmark", "channelID", channelID, "bookmarkID", bookmark.ID)
+		return nil, err
+	}
+	return &response.Bookmark, nil
+}
+
+// RemoveBookmark removes the bookmark from the slack channel
+func (s *SlackService) RemoveBookmark(channelID string, bookmarkID string) error {
+	err := s.callAPI("bookmarks.remove", url.Values{
+		"channel_id":  {channelID},
+		"bookmark_id": {bookmarkID},
+	}, nil)
+	if err != nil {
+		s.log.Error(err, "Error removing bookmark", "channelID", channelID, "bookmarkID", bookmarkID)
+		return err
+	}
+	return nil
+}
+
+// messageOptions returns the options of a message with the text and the blocks, given as a JSON array of Block Kit
+// blocks. The text is the fallback for notifications if there are blocks.
+func messageOptions(text string, blocks string) ([]slack.MsgOption, error) {
+	options := []slack.MsgOption{slack.MsgOptionText(text, false)}
+
+	if blocks != "" {
+		blockSet := slack.Blocks{}
+		err := json.Unmarshal([]byte(blocks), &blockSet)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid blocks: %s", err.Error())
+		}
+		options = append(options, slack.MsgOptionBlocks(blockSet.BlockSet...))
+	}
+	return options, nil
+}
+
+// PostMessage posts a message with the text and the optional blocks to the slack channel and returns its timestamp
+func (s *SlackService) PostMessage(channelID string, text string, blocks string) (string, error) {
+	options, err := messageOptions(text, blocks)
+	if err != nil {
+		return "", err
+	}
+
+	_, timestamp, err := s.client().PostMessage(channelID, options...)
+	if err != nil {
+		s.log.Error(err, "Error posting message", "channelID", channelID)
+		return "", err
+	}
+	return timestamp, nil
+}
+
+// ReplyToMessage posts a message with the text and the optional blocks in the thread of the message with the timestamp
+// and returns its timestamp
+func (s *SlackService) ReplyToMessage(channelID string, threadTimestamp string, text string, blocks string) (string, error) {
+	options, err := messageOptions(text, blocks)
+	if err != nil {
+		return "", err
+	}
+
+	_, timestamp, err := s.client().PostMessage(channelID, append(options, slack.MsgOptionTS(threadTimestamp))...)
+	if err != nil {
+		s.log.Error(err, "Error replying to message", "channelID", channelID, "threadTimestamp", threadTimestamp)
+		return "", err
+	}
+	return timestamp, nil
+}
+
+// UpdateMessage replaces the text and the blocks of the message with the timestamp
+func (s *SlackService) UpdateMessage(channelID string, timestamp string, text string, blocks string) error {
+	options, err := messageOptions(text, blocks)
+	if err != nil {
+		return err
+	}
+
+	_, _, _, err = s.client().UpdateMessage(channelID, timestamp, options...)
+	if err != nil {
+		s.log.Error(err, "Error updating message", "channelID", channelID, "timestamp", timestamp)
+		return err
+	}
+	return nil
+}
+
+// DeleteMessage deletes the message with the timestamp, messages that were already deleted are ignored
+func (s *SlackService) DeleteMessage(channelID string, timestamp string) error {
+	_, _, err := s.client().DeleteMessage(channelID, timestamp)
+	if err != nil && err.Error() != "message_not_found" {
+		s.log.Error(err, "Error deleting message", "channelID", channelID, "timestamp", timestamp)
+		return err
+	}
+	return nil
+}
+
+// ListPinnedMessages returns the text of the messages pinned to the slack channel by their timestamps
+func (s *SlackService) ListPinnedMessages(channelID string) (map[string]string, error) {
+	items, _, err := s.client().ListPins(channelID)
+	if err != nil {
+		s.log.Error(err, "Error listing pinned messages", "channelID", channelID)
+		return nil, err
+	}
+
+	messages := map[string]string{}
+	for _, item := range items {
+		if item.Message != nil {
+			messages[item.Message.Timestamp] = item.Message.Text
+		}
+	}
+	return messages, nil
+}
+
+// PinMessage pins the message with the timestamp to the slack channel
+func (s *SlackService) PinMessage(channelID string, timestamp string) error {
+	err := s.client().AddPin(channelID, slack.NewRefToMessage(channelID, timestamp))
+	if err != nil && err.Error() != "already_pinned" {
+		s.log.Error(err, "Error pinning message", "channelID", channelID, "timestamp", timestamp)
+		return err
+	}
+	return nil
+}
+
+// UnpinMessage unpins the message with the timestamp from the slack channel, messages that are not pinned or were
+// deleted are ignored
+func (s *SlackService) UnpinMessage(channelID string, timestamp string) error {
+	err := s.client().RemovePin(channelID, slack.NewRefToMessage(channelID, timestamp))
+	if err != nil && err.Error() != "no_pin" && err.Error() != "message_not_found" {
+		s.log.Error(err, "Error unpinning message", "channelID", channelID, "timestamp", timestamp)
 		return err
 	}
 	return nil
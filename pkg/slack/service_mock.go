@@ -2,7 +2,7 @@ package slack
 
 import (
 	"github.com/go-logr/logr"
-	"github.com/slack-go/slack"
+	"github.com/stakater/slack-operator/pkg/config"
 	"github.com/stakater/slack-operator/pkg/slack/mock"
 )
 
@@ -17,12 +17,7 @@ func NewMockService(log logr.Logger) *SlackService {
 
 		log.Info("Starting Test Server", "url", testServer.GetAPIURL())
 
-		opts := slack.OptionAPIURL(testServer.GetAPIURL())
-
-		mockSlackService = &SlackService{
-			api: slack.New("apitoken", opts),
-			log: log.WithName("SlackService"),
-		}
+		mockSlackService = newService("apitoken", config.DefaultUserCacheTTL, log.WithName("SlackService"), testServer.GetAPIURL())
 	}
 
 	return mockSlackService
@@ -2,8 +2,12 @@ package slack
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/config"
 	"github.com/stakater/slack-operator/pkg/slack/mock"
 	"github.com/stretchr/testify/assert"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -79,14 +83,423 @@ func TestSlackService_ArchiveChannel_shouldThrowError_whenChannelNotFound(t *tes
 
 func TestSlackService_InviteUsers_shouldSendUserInvites_whenUserExists(t *testing.T) {
 	s := NewMockService(log)
-	errs := s.InviteUsers(mock.PublicConversationID, []string{mock.ExistingUserEmail})
-	assert.Equal(t, 0, len(errs))
+	members, err := s.InviteUsers(mock.PublicConversationID, []string{mock.ExistingUserEmail})
+	assert.NoError(t, err)
+	assert.Equal(t, []slackv1alpha1.MemberStatus{
+		{Email: mock.ExistingUserEmail, UserID: "W012A3CDE", State: slackv1alpha1.JoinedMemberState},
+	}, members)
+}
+
+func TestSlackService_InviteUsers_shouldReportState_whenUserCanNotJoin(t *testing.T) {
+	s := NewMockService(log)
+	emailList := []string{"spengler@ghostbusters.example.com", "deactivated@slack.com", mock.ExistingUserEmail}
+	members, err := s.InviteUsers(mock.PublicConversationID, emailList)
+	assert.NoError(t, err)
+	assert.Len(t, members, 3)
+
+	assert.Equal(t, slackv1alpha1.NotFoundMemberState, members[0].State)
+	assert.Equal(t, fmt.Sprintf("Error fetching user by Email %s", emailList[0]), members[0].LastError)
+
+	assert.Equal(t, slackv1alpha1.DeactivatedMemberState, members[1].State)
+	assert.Equal(t, "W07QCRPA4", members[1].UserID)
+
+	assert.Equal(t, slackv1alpha1.JoinedMemberState, members[2].State)
+}
+
+func TestSlackService_GetChannelByName_shouldReturnChannel_whenChannelExists(t *testing.T) {
+	s := NewMockService(log)
+	channel, err := s.GetChannelByName(mock.AdoptableConversationName)
+	assert.NoError(t, err)
+	assert.Equal(t, mock.PublicConversationID, channel.ID)
+}
+
+func TestSlackService_GetChannelByName_shouldThrowError_whenChannelDoesNotExist(t *testing.T) {
+	s := NewMockService(log)
+	_, err := s.GetChannelByName("missing-channel")
+	assert.EqualError(t, err, fmt.Sprintf(ChannelNotFoundError, "missing-channel"))
+}
+
+func TestSlackService_GetExtraUsers_shouldReturnUsersNotInList(t *testing.T) {
+	s := NewMockService(log)
+	users, err := s.GetExtraUsers(mock.PublicConversationID, []string{mock.ExistingUserEmail})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, users)
+	for _, user := range users {
+		assert.NotEqual(t, mock.ExistingUserEmail, user.Profile.Email)
+	}
+}
+
+func TestSlackService_GetExtraUsers_shouldIgnoreCaseOfEmails(t *testing.T) {
+	s := NewMockService(log)
+	users, err := s.GetExtraUsers(mock.PublicConversationID, []string{strings.ToUpper(mock.ExistingUserEmail)})
+	assert.NoError(t, err)
+	for _, user := range users {
+		assert.NotEqual(t, mock.ExistingUserEmail, user.Profile.Email)
+	}
+}
+
+func TestSlackService_InviteUsers_shouldLookUpUnknownEmailOnce(t *testing.T) {
+	s := NewMockService(log)
+	mock.ResetCalls()
+
+	for i := 0; i < 2; i++ {
+		members, err := s.InviteUsers(mock.PublicConversationID, []string{"nonexistent@slack.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, slackv1alpha1.NotFoundMemberState, members[0].State)
+	}
+	assert.Len(t, mock.Calls("users.lookupByEmail"), 1)
+}
+
+func TestSlackService_GetExtraUsers_shouldNotReturnExternalMembers(t *testing.T) {
+	s := NewMockService(log)
+	users, err := s.GetExtraUsers(mock.PublicConversationID, []string{mock.ExistingUserEmail})
+	assert.NoError(t, err)
+	for _, user := range users {
+		assert.NotEqual(t, mock.ExternalUserEmail, user.Profile.Email)
+	}
+}
+
+func TestSlackService_GetChannelDrift_shouldReturnDrift_whenChannelDiffers(t *testing.T) {
+	s := NewMockService(log)
+	channel := &slackv1alpha1.Channel{
+		Spec: slackv1alpha1.ChannelSpec{
+			Name:  "new-channel",
+			Topic: "myTopic",
+			Users: []string{mock.ExistingUserEmail},
+		},
+		Status: slackv1alpha1.ChannelStatus{
+			ID: mock.PublicConversationID,
+		},
+	}
+
+	drift, err := s.GetChannelDrift(channel, channel.Spec.Users, slackv1alpha1.AuthoritativeMembershipPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, &slackv1alpha1.FieldDrift{Desired: "new-channel", Actual: mock.ConversationName}, drift.Name)
+	assert.Equal(t, &slackv1alpha1.FieldDrift{Desired: "myTopic", Actual: ""}, drift.Topic)
+	assert.Nil(t, drift.Description)
+	assert.Empty(t, drift.MissingUsers)
+	assert.NotEmpty(t, drift.ExtraUsers)
+}
+
+func TestSlackService_GetChannelDrift_shouldNotReturnExtraUsers_whenMembershipIsAdditive(t *testing.T) {
+	s := NewMockService(log)
+	channel := &slackv1alpha1.Channel{
+		Spec: slackv1alpha1.ChannelSpec{
+			Name:  mock.ConversationName,
+			Users: []string{mock.ExistingUserEmail},
+		},
+		Status: slackv1alpha1.ChannelStatus{
+			ID: mock.PublicConversationID,
+		},
+	}
+
+	drift, err := s.GetChannelDrift(channel, channel.Spec.Users, slackv1alpha1.AdditiveMembershipPolicy)
+	assert.NoError(t, err)
+	assert.Nil(t, drift)
+}
+
+func TestSlackService_GetChannelDrift_shouldNotReturnMissingUsers_whenUsersCantBeInvited(t *testing.T) {
+	s := NewMockService(log)
+	channel := &slackv1alpha1.Channel{
+		Spec: slackv1alpha1.ChannelSpec{
+			Name:  mock.ConversationName,
+			Users: []string{mock.ExistingUserEmail, "spengler@ghostbusters.example.com", mock.DeactivatedUserEmail},
+		},
+		Status: slackv1alpha1.ChannelStatus{
+			ID: mock.PublicConversationID,
+		},
+	}
+
+	drift, err := s.GetChannelDrift(channel, channel.Spec.Users, slackv1alpha1.AdditiveMembershipPolicy)
+	assert.NoError(t, err)
+	assert.Nil(t, drift)
+}
+
+func TestSlackService_GetChannelDrift_shouldNotReturnMissingUsers_whenMembershipIsIgnored(t *testing.T) {
+	s := NewMockService(log)
+	channel := &slackv1alpha1.Channel{
+		Spec: slackv1alpha1.ChannelSpec{
+			Name:  mock.ConversationName,
+			Users: []string{"spengler@ghostbusters.example.com"},
+		},
+		Status: slackv1alpha1.ChannelStatus{
+			ID: mock.PublicConversationID,
+		},
+	}
+
+	drift, err := s.GetChannelDrift(channel, channel.Spec.Users, slackv1alpha1.IgnoreMembershipPolicy)
+	assert.NoError(t, err)
+	assert.Nil(t, drift)
+}
+
+func TestSlackService_GetChannelMembers_shouldAddUserGroupMembers(t *testing.T) {
+	s := NewMockService(log)
+	channel := &slackv1alpha1.Channel{
+		Spec: slackv1alpha1.ChannelSpec{
+			Users:      []string{mock.ExistingUserEmail, "spectre@ghostbusters.example.com"},
+			UserGroups: []string{"@" + mock.UserGroupHandle},
+		},
+	}
+
+	members, err := s.GetChannelMembers(channel)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{mock.ExistingUserEmail, "spectre@ghostbusters.example.com", "venkman@ghostbusters.example.com"}, members)
+}
+
+func TestSlackService_GetChannelMembers_shouldFindUserGroupByID(t *testing.T) {
+	s := NewMockService(log)
+	channel := &slackv1alpha1.Channel{
+		Spec: slackv1alpha1.ChannelSpec{
+			UserGroups: []string{mock.UserGroupID},
+		},
+	}
+
+	members, err := s.GetChannelMembers(channel)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{mock.ExistingUserEmail, "venkman@ghostbusters.example.com"}, members)
+}
+
+func TestSlackService_GetChannelMembers_shouldThrowError_whenUserGroupDoesNotExist(t *testing.T) {
+	s := NewMockService(log)
+	channel := &slackv1alpha1.Channel{
+		Spec: slackv1alpha1.ChannelSpec{
+			Users:      []string{mock.ExistingUserEmail},
+			UserGroups: []string{"ectoplasm"},
+		},
+	}
+
+	_, err := s.GetChannelMembers(channel)
+	assert.EqualError(t, err, fmt.Sprintf(UserGroupNotFoundError, "ectoplasm"))
+}
+
+func TestSlackService_SetToken_shouldRejectToken_whenTokenIsInvalid(t *testing.T) {
+	s := NewMockService(log)
+
+	err := s.SetToken(mock.InvalidToken)
+	assert.Error(t, err)
+	assert.Error(t, s.CheckToken(nil))
+
+	err = s.SetToken("apitoken")
+	assert.NoError(t, err)
+	assert.NoError(t, s.CheckToken(nil))
+}
+
+func TestSlackService_SetToken_shouldKeepReconciling_whenRotatedTokenIsInvalid(t *testing.T) {
+	s := NewMockService(log)
+
+	_ = s.SetToken(mock.InvalidToken)
+	assert.NoError(t, s.TokenError())
+	assert.Error(t, s.CheckToken(nil))
+
+	_ = s.SetToken("apitoken")
+}
+
+func TestSlackService_SetToken_shouldReportTokenError_whenFirstTokenIsInvalid(t *testing.T) {
+	s := newService("", config.DefaultUserCacheTTL, log, NewMockService(log).apiURL)
+
+	err := s.SetToken(mock.InvalidToken)
+	assert.Error(t, err)
+	assert.EqualError(t, s.TokenError(), err.Error())
+	assert.EqualError(t, s.CheckToken(nil), err.Error())
+}
+
+func TestSlackService_SetToken_shouldKeepClient_whenTokenIsInvalid(t *testing.T) {
+	s := NewMockService(log)
+	api := s.client()
+
+	_ = s.SetToken(mock.InvalidToken)
+	assert.Same(t, api, s.client())
+
+	_ = s.SetToken("apitoken")
+	assert.NotSame(t, api, s.client())
+}
+
+func TestSlackService_TokenError_shouldReturnError_whenTokenIsNotLoaded(t *testing.T) {
+	s := New("", log)
+
+	assert.EqualError(t, s.TokenError(), TokenNotLoadedError)
+	assert.EqualError(t, s.CheckToken(nil), TokenNotLoadedError)
+	assert.NoError(t, New("xoxb-token", log).TokenError())
+}
+
+func TestSlackService_InvalidateUsers_shouldOnlyInvalidateUsersOfTheWorkspace(t *testing.T) {
+	s := newService("apitoken", config.DefaultUserCacheTTL, log, NewMockService(log).apiURL)
+	teamID, err := s.teamID()
+	assert.NoError(t, err)
+	assert.NoError(t, s.users.refresh())
+
+	s.InvalidateUsers("T0OTHERTEAM")
+	assert.True(t, time.Now().Before(s.users.expiresAt))
+
+	s.InvalidateUsers(teamID)
+	assert.False(t, time.Now().Before(s.users.expiresAt))
 }
 
-func TestSlackService_InviteUsers_shouldThowError_whenUserDoesNotExists(t *testing.T) {
+func TestSlackService_CreateUserGroup_shouldCreateUserGroup(t *testing.T) {
 	s := NewMockService(log)
-	emailList := []string{"spengler@ghostbusters.example.com"}
-	errs := s.InviteUsers(mock.PublicConversationID, emailList)
-	assert.Equal(t, 1, len(errs))
-	assert.EqualError(t, errs[0], fmt.Sprintf("Error fetching user by Email %s", emailList[0]))
+
+	id, err := s.CreateUserGroup("ghostbusters-oncall", "Ghostbusters On-Call", "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, mock.NewUserGroupID, *id)
+}
+
+func TestSlackService_CreateUserGroup_shouldThrowError_whenUserGroupWithSameHandleExists(t *testing.T) {
+	s := NewMockService(log)
+
+	_, err := s.CreateUserGroup(mock.UserGroupHandle, "Ghostbusters", "", nil)
+	assert.EqualError(t, err, "name_already_exists")
+}
+
+func TestSlackService_GetUserGroupByHandle_shouldReturnUserGroup_whenUserGroupExists(t *testing.T) {
+	s := NewMockService(log)
+
+	userGroup, err := s.GetUserGroupByHandle(mock.UserGroupHandle)
+	assert.NoError(t, err)
+	assert.Equal(t, mock.UserGroupID, userGroup.ID)
+}
+
+func TestSlackService_SetUserGroupMembers_shouldThrowError_whenUserDoesNotExist(t *testing.T) {
+	s := NewMockService(log)
+
+	userGroup, err := s.GetUserGroup(mock.UserGroupID)
+	assert.NoError(t, err)
+
+	errorlist := s.SetUserGroupMembers(userGroup, []string{mock.ExistingUserEmail, "nonexistent@slack.com"})
+	assert.Len(t, errorlist, 1)
+	assert.EqualError(t, errorlist[0], "Error fetching user by Email nonexistent@slack.com")
+}
+
+func TestSlackService_DisableUserGroup_shouldThrowError_whenUserGroupNotFound(t *testing.T) {
+	s := NewMockService(log)
+
+	err := s.DisableUserGroup(mock.NotFoundUserGroupID)
+	assert.EqualError(t, err, "no_such_subteam")
+}
+
+func TestSlackService_AddChannelManagers_shouldAssignRole(t *testing.T) {
+	s := NewMockService(log)
+
+	err := s.AddChannelManagers(mock.PublicConversationID, []string{mock.ExistingUserEmail})
+	assert.NoError(t, err)
+}
+
+func TestSlackService_AddChannelManagers_shouldThrowError_whenChannelNotFound(t *testing.T) {
+	s := NewMockService(log)
+
+	err := s.AddChannelManagers(mock.NotFoundConversationID, []string{mock.ExistingUserEmail})
+	assert.EqualError(t, err, "channel_not_found")
+}
+
+func TestSlackService_RemoveChannelManagers_shouldThrowError_whenUserDoesNotExist(t *testing.T) {
+	s := NewMockService(log)
+
+	err := s.RemoveChannelManagers(mock.PublicConversationID, []string{"nonexistent@slack.com"})
+	assert.EqualError(t, err, "Error fetching user by Email nonexistent@slack.com")
+}
+
+func TestSlackService_SetPostingPolicy_shouldSetWhoCanPost(t *testing.T) {
+	s := NewMockService(log)
+
+	err := s.SetPostingPolicy(mock.PublicConversationID, slackv1alpha1.ManagersPostingPolicy, []string{mock.ExistingUserEmail})
+	assert.NoError(t, err)
+}
+
+func TestSlackService_InviteShared_shouldReturnInviteID(t *testing.T) {
+	s := NewMockService(log)
+
+	inviteID, err := s.InviteShared(mock.PublicConversationID, mock.ExternalUserEmail, true)
+	assert.NoError(t, err)
+	assert.Equal(t, mock.InviteID, inviteID)
+}
+
+func TestSlackService_InviteShared_shouldThrowError_whenChannelNotFound(t *testing.T) {
+	s := NewMockService(log)
+
+	_, err := s.InviteShared(mock.NotFoundConversationID, mock.ExternalUserEmail, false)
+	assert.EqualError(t, err, "channel_not_found")
+}
+
+func TestSlackService_GetExternalMembers_shouldReturnMembersOfOtherOrganizations(t *testing.T) {
+	s := NewMockService(log)
+
+	members, err := s.GetExternalMembers(mock.PublicConversationID)
+	assert.NoError(t, err)
+	assert.Len(t, members, 1)
+	assert.Equal(t, mock.ExternalTeamID, members[0].TeamID)
+}
+
+func TestSlackService_ListBookmarks_shouldReturnBookmarks(t *testing.T) {
+	s := NewMockService(log)
+
+	bookmarks, err := s.ListBookmarks(mock.PublicConversationID)
+	assert.NoError(t, err)
+	assert.Equal(t, []Bookmark{{ID: mock.BookmarkID, Title: mock.BookmarkTitle, Link: mock.BookmarkLink, Emoji: ":book:"}}, bookmarks)
+}
+
+func TestSlackService_AddBookmark_shouldReturnBookmarkID(t *testing.T) {
+	s := NewMockService(log)
+
+	bookmark, err := s.AddBookmark(mock.PublicConversationID, Bookmark{Title: "Dashboard", Link: "https://grafana.example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, mock.NewBookmarkID, bookmark.ID)
+	assert.Equal(t, "Dashboard", bookmark.Title)
+}
+
+func TestSlackService_EditBookmark_shouldThrowError_whenBookmarkNotFound(t *testing.T) {
+	s := NewMockService(log)
+
+	_, err := s.EditBookmark(mock.PublicConversationID, Bookmark{ID: "Bk00NOTFOUND", Title: "Dashboard", Link: "https://grafana.example.com"})
+	assert.EqualError(t, err, "not_found")
+}
+
+func TestSlackService_PostMessage_shouldReturnTimestamp(t *testing.T) {
+	s := NewMockService(log)
+
+	timestamp, err := s.PostMessage(mock.PublicConversationID, "Welcome", `[{"type": "section", "text": {"type": "mrkdwn", "text": "*Welcome*"}}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, mock.NewMessageTimestamp, timestamp)
+}
+
+func TestSlackService_PostMessage_shouldThrowError_whenBlocksAreInvalid(t *testing.T) {
+	s := NewMockService(log)
+
+	_, err := s.PostMessage(mock.PublicConversationID, "Welcome", `{"type": "section"}`)
+	assert.Error(t, err)
+}
+
+func TestSlackService_DeleteMessage_shouldIgnoreDeletedMessage(t *testing.T) {
+	s := NewMockService(log)
+
+	err := s.DeleteMessage(mock.PublicConversationID, "1503435958.000249")
+	assert.NoError(t, err)
+}
+
+func TestSlackService_ListPinnedMessages_shouldReturnTextsByTimestamp(t *testing.T) {
+	s := NewMockService(log)
+
+	messages, err := s.ListPinnedMessages(mock.PublicConversationID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{mock.PinnedMessageTimestamp: mock.PinnedMessageText}, messages)
+}
+
+func TestSlackService_PinMessage_shouldThrowError_whenMessageNotFound(t *testing.T) {
+	s := NewMockService(log)
+
+	err := s.PinMessage(mock.PublicConversationID, "1503435958.000249")
+	assert.EqualError(t, err, "message_not_found")
+}
+
+func TestSlackService_UnpinMessage_shouldIgnoreDeletedMessage(t *testing.T) {
+	s := NewMockService(log)
+
+	err := s.UnpinMessage(mock.PublicConversationID, "1503435958.000249")
+	assert.NoError(t, err)
+}
+
+func TestSlackService_ReplyToMessage_shouldReturnTimestamp(t *testing.T) {
+	s := NewMockService(log)
+
+	timestamp, err := s.ReplyToMessage(mock.PublicConversationID, mock.PinnedMessageTimestamp, "Resolved", "")
+	assert.NoError(t, err)
+	assert.Equal(t, mock.NewMessageTimestamp, timestamp)
 }
@@ -2,10 +2,12 @@ package pkgutil
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/slack-go/slack"
 	"github.com/stakater/slack-operator/pkg/config"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,7 +16,6 @@ import (
 	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
-	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
 )
 
 // MapErrorListToError maps multiple errors into a single error
@@ -32,13 +33,56 @@ func MapErrorListToError(errs []error) error {
 	return fmt.Errorf(strings.Join(errMsg, "\n"))
 }
 
-func ManageError(ctx context.Context, client k8sClient.Client, channelInstance *slackv1alpha1.Channel, issue error) (ctrl.Result, error) {
+// SameElements returns true if both lists contain the same elements, ignoring their order
+func SameElements(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	count := map[string]int{}
+	for _, element := range a {
+		count[element]++
+	}
+	for _, element := range b {
+		count[element]--
+		if count[element] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Difference returns the elements of a which are not in b
+func Difference(a []string, b []string) []string {
+	inB := map[string]bool{}
+	for _, element := range b {
+		inB[element] = true
+	}
+
+	var difference []string
+	for _, element := range a {
+		if !inB[element] {
+			difference = append(difference, element)
+		}
+	}
+	return difference
+}
+
+// Resource is a kubernetes resource whose status conditions report the result of the reconcile
+type Resource interface {
+	k8sClient.Object
+	reconcilerUtil.ConditionsStatusAware
+}
+
+// ManageError sets the error condition on the resource and requeues it, after the delay requested by slack if the
+// request was rate limited
+func ManageError(ctx context.Context, client k8sClient.Client, instance Resource, issue error) (ctrl.Result, error) {
 
 	// Base object for patch, which patches using the merge-patch strategy with the given object as base.
-	channelInstancePatchBase := k8sClient.MergeFrom(channelInstance.DeepCopy())
+	instancePatchBase := k8sClient.MergeFrom(instance.DeepCopyObject().(k8sClient.Object))
 
 	// Update status
-	channelInstance.Status.Conditions = []metav1.Condition{
+	instance.SetReconcileStatus([]metav1.Condition{
 		{
 			Type:               "ReconcileError",
 			LastTransitionTime: metav1.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), time.Now().Hour(), time.Now().Minute(), 0, 0, time.Now().Location()),
@@ -46,13 +90,19 @@ func ManageError(ctx context.Context, client k8sClient.Client, channelInstance *
 			Reason:             reconcilerUtil.FailedReason,
 			Status:             metav1.ConditionTrue,
 		},
-	}
+	})
 
 	// Patch status
-	err := client.Status().Patch(ctx, channelInstance, channelInstancePatchBase)
+	err := client.Status().Patch(ctx, instance, instancePatchBase)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return reconcilerUtil.RequeueAfter(config.ErrorRequeueTime)
+	// Requeue after the delay requested by slack when the request was rate limited
+	var rateLimitedError *slack.RateLimitedError
+	if errors.As(issue, &rateLimitedError) {
+		return reconcilerUtil.RequeueAfter(rateLimitedError.RetryAfter)
+	}
+
+	return reconcilerUtil.RequeueAfter(config.Get().Requeue.ErrorInterval.Duration)
 }